@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/cache"
+)
+
+// cacheFlags holds the on-disk cache options given on the command line
+type cacheFlags struct {
+	enabled bool
+	offline bool
+	ttl     time.Duration
+}
+
+func (f *cacheFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.enabled, "cache", false, "cache locations and current conditions on disk (default $SKY_CACHE)")
+	fs.BoolVar(&f.offline, "offline", false, "answer from the cache only, without network requests")
+	fs.DurationVar(&f.ttl, "cache-ttl", 0, "how long cached conditions stay fresh (default $SKY_CACHE_TTL or 10m)")
+}
+
+// wrap puts the on-disk cache in front of the geocoder and weather provider.
+// The cache is opt-in: it is used with --cache, --offline or SKY_CACHE=1.
+func (f *cacheFlags) wrap(s *services, units api.Units, getenv func(string) string) error {
+	enabled := f.enabled || f.offline
+	if env := getenv("SKY_CACHE"); env != "" && !enabled {
+		var err error
+		if enabled, err = strconv.ParseBool(env); err != nil {
+			return fmt.Errorf("invalid SKY_CACHE %q (want 1 or 0)", env)
+		}
+	}
+	if !enabled {
+		return nil
+	}
+
+	ttl := f.ttl
+	if ttl == 0 {
+		ttl = cache.DefaultWeatherTTL
+		if env := getenv("SKY_CACHE_TTL"); env != "" {
+			var err error
+			if ttl, err = time.ParseDuration(env); err != nil {
+				return fmt.Errorf("invalid SKY_CACHE_TTL %q (want a duration such as 10m)", env)
+			}
+		}
+	}
+	if ttl < 0 {
+		return fmt.Errorf("cache TTL cannot be negative")
+	}
+
+	dir, err := cache.Dir(getenv)
+	if err != nil {
+		return err
+	}
+	store := cache.NewStore(dir)
+
+	s.geocoder = &cache.Geocoder{Next: s.geocoder, Store: store, TTL: cache.DefaultLocationTTL, Offline: f.offline, Language: getenv("SKY_LANGUAGE")}
+	s.weather = &cache.Weather{Next: s.weather, Store: store, Units: units, TTL: ttl, Offline: f.offline}
+	return nil
+}
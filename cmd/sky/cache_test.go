@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+func TestRun_Cache(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{"Flag", []string{"--cache", "New York"}, nil},
+		{"Environment", []string{"New York"}, map[string]string{"SKY_CACHE": "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.env["XDG_CACHE_HOME"] = t.TempDir()
+			for k, v := range tt.env {
+				ta.env[k] = v
+			}
+
+			assert.Equal(t, 0, ta.run(tt.args))
+			assert.Equal(t, 0, ta.run(tt.args), ta.stderr.String())
+
+			assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+			assert.Equal(t, 1, ta.weather.calls)
+			assert.NotContains(t, ta.stdout.String(), "(cached")
+		})
+	}
+}
+
+func TestRun_CacheDisabledByDefault(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["XDG_CACHE_HOME"] = t.TempDir()
+
+	ta.run([]string{"New York"})
+	ta.run([]string{"New York"})
+
+	assert.Equal(t, 2, ta.weather.calls)
+}
+
+func TestRun_CacheTTL(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["XDG_CACHE_HOME"] = t.TempDir()
+
+	ta.run([]string{"--cache", "--cache-ttl", "0s", "New York"})
+	ta.run([]string{"--cache", "--cache-ttl", "0s", "New York"})
+
+	// A zero flag falls back to the default TTL
+	assert.Equal(t, 1, ta.weather.calls)
+
+	ta.run([]string{"--cache", "--cache-ttl", "1ns", "New York"})
+	assert.Equal(t, 2, ta.weather.calls)
+}
+
+func TestRun_StaleOnError(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["XDG_CACHE_HOME"] = t.TempDir()
+	ta.env["SKY_CACHE_TTL"] = "1ns"
+	assert.Equal(t, 0, ta.run([]string{"--cache", "New York"}))
+
+	ta.stdout.Reset()
+	ta.weather.err = &api.NetworkError{What: "weather", Err: errors.New("connection refused")}
+	code := ta.run([]string{"--cache", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "Temp: 21.3°C")
+	assert.Contains(t, ta.stdout.String(), "(cached")
+}
+
+func TestRun_Offline(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["XDG_CACHE_HOME"] = t.TempDir()
+
+	code := ta.run([]string{"--offline", "New York"})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, ta.stderr.String(), "no cached data")
+	assert.Equal(t, 0, ta.weather.calls)
+
+	assert.Equal(t, 0, ta.run([]string{"--cache", "New York"}))
+	ta.stdout.Reset()
+	code = ta.run([]string{"--offline", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, 1, ta.weather.calls)
+	assert.Contains(t, ta.stdout.String(), "Temp: 21.3°C")
+	assert.Contains(t, ta.stdout.String(), "(cached")
+}
+
+func TestRun_OfflineInteractiveSkipsPicker(t *testing.T) {
+	ta := newTestApp("Paris\n")
+	ta.env["XDG_CACHE_HOME"] = t.TempDir()
+
+	ta.run([]string{"--offline"})
+
+	assert.Empty(t, ta.geocoder.queries)
+	assert.NotContains(t, ta.stdout.String(), "Choose a location")
+}
+
+func TestRun_CacheUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{"Offline forecast", []string{"forecast", "--offline", "Berlin"}, nil},
+		{"Offline air", []string{"--offline", "--air", "Berlin"}, nil},
+		{"Invalid SKY_CACHE", []string{"Berlin"}, map[string]string{"SKY_CACHE": "sometimes"}},
+		{"Invalid SKY_CACHE_TTL", []string{"--cache", "Berlin"}, map[string]string{"SKY_CACHE_TTL": "soon"}},
+		{"Negative TTL", []string{"--cache", "--cache-ttl=-1m", "Berlin"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.env["XDG_CACHE_HOME"] = t.TempDir()
+			for k, v := range tt.env {
+				ta.env[k] = v
+			}
+
+			assert.Equal(t, 2, ta.run(tt.args))
+			assert.Contains(t, ta.stderr.String(), "Error: ")
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// command is a sky subcommand
+type command struct {
+	name string
+
+	// args describes the positional arguments in the usage line
+	args string
+
+	// summary is the one-line description shown in help
+	summary string
+
+	// actions are the words completed after the command name, if any
+	actions []string
+
+	// flags registers the command's flags, storing their values in o
+	flags func(fs *flag.FlagSet, o *options)
+
+	// run executes the command with its positional arguments
+	run func(a *app, o *options, args []string) int
+}
+
+// options holds the flag values of all commands; each command registers
+// the flags it understands
+type options struct {
+	hours, days, count int
+
+	// interval is the time between updates in watch mode
+	interval time.Duration
+
+	detail, air, art, chart, multi, pick bool
+
+	templateText, templateFile string
+
+	format, color, palette string
+
+	// profile selects a profile of the configuration file
+	profile string
+
+	units unitFlags
+	cache cacheFlags
+}
+
+// defaultCommand runs when the first argument names no command
+const defaultCommand = "now"
+
+// commands are the sky subcommands in the order shown in help
+var commands []*command
+
+// The command table is built in init because the completion command reads it
+func init() {
+	commands = []*command{
+		{
+			name:    "now",
+			args:    "[location]",
+			summary: "Show current conditions (the default command)",
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.BoolVar(&o.detail, "detail", false, "show humidity, wind, pressure and other current conditions")
+				fs.BoolVar(&o.air, "air", false, "also show air quality with the current conditions")
+				fs.BoolVar(&o.art, "art", false, "draw ASCII art of the current conditions instead of emoji")
+				fs.BoolVar(&o.multi, "m", false, "treat each argument as a separate location (shorthand for --multi)")
+				fs.BoolVar(&o.multi, "multi", false, "treat each argument as a separate location")
+				fs.StringVar(&o.templateText, "template", "", "render current conditions with a Go template (see docs/templates.md)")
+				fs.StringVar(&o.templateFile, "template-file", "", "read the --template from a file")
+				weatherFlags(fs, o)
+			},
+			run: weatherCommand("now"),
+		},
+		{
+			name:    "hourly",
+			args:    "[location]",
+			summary: "Show an hour-by-hour forecast",
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.IntVar(&o.hours, "hours", api.DefaultForecastHours, "number of hours to show")
+				fs.BoolVar(&o.chart, "chart", false, "draw the forecast as a chart")
+				weatherFlags(fs, o)
+			},
+			run: weatherCommand("hourly"),
+		},
+		{
+			name:    "forecast",
+			args:    "[location]",
+			summary: "Show a day-by-day forecast",
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.IntVar(&o.days, "days", api.DefaultForecastDays, "number of days to show")
+				fs.BoolVar(&o.chart, "chart", false, "draw the forecast as a chart")
+				weatherFlags(fs, o)
+			},
+			run: weatherCommand("forecast"),
+		},
+		{
+			name:    "air",
+			args:    "[location]",
+			summary: "Show air quality and pollen",
+			flags:   weatherFlags,
+			run:     weatherCommand("air"),
+		},
+		{
+			name:    "watch",
+			args:    "[location]",
+			summary: "Keep current conditions on screen, refreshing them until Ctrl+C",
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.DurationVar(&o.interval, "interval", defaultWatchInterval, "time between updates, at least 1m")
+				fs.BoolVar(&o.detail, "detail", false, "show humidity, wind, pressure and other current conditions")
+				fs.BoolVar(&o.art, "art", false, "draw ASCII art of the current conditions instead of emoji")
+				o.units.register(fs)
+				outputFlags(fs, o)
+			},
+			run: weatherCommand("watch"),
+		},
+		{
+			name:    "tui",
+			args:    "[location]",
+			summary: "Show a full-screen dashboard of a place and your favorites",
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.DurationVar(&o.interval, "interval", defaultWatchInterval, "time between updates, at least 1m")
+				o.units.register(fs)
+				outputFlags(fs, o)
+			},
+			run: weatherCommand("tui"),
+		},
+		{
+			name:    "search",
+			args:    "[name]",
+			summary: "List the places matching a name",
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.IntVar(&o.count, "count", api.DefaultSearchCount, "number of candidates to list")
+				outputFlags(fs, o)
+			},
+			run: weatherCommand("search"),
+		},
+		{
+			name:    "config",
+			args:    "[get <setting> | set <setting> <value> | edit | path]",
+			summary: "Show or change the settings of the configuration file",
+			actions: configActions,
+			flags:   profileFlag,
+			run:     (*app).runConfig,
+		},
+		{
+			name:    "fav",
+			args:    "add <alias> <location> | list | rm <alias>...",
+			summary: "Save places under aliases such as @home",
+			actions: favActions,
+			flags: func(fs *flag.FlagSet, o *options) {
+				fs.BoolVar(&o.pick, "pick", false, "choose among the matching places instead of taking the best match")
+				profileFlag(fs, o)
+			},
+			run: (*app).runFav,
+		},
+		{
+			name:    "completion",
+			args:    "bash|zsh|fish",
+			summary: "Print a shell completion script",
+			actions: completionShells,
+			flags:   func(fs *flag.FlagSet, o *options) {},
+			run:     (*app).runCompletion,
+		},
+	}
+}
+
+// outputFlags registers the flags selecting the output format and colors
+func outputFlags(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.format, "format", "", "output format: text or json (default $SKY_FORMAT or text)")
+	fs.StringVar(&o.color, "color", "", "colorize output: auto, always or never (default $SKY_COLOR or auto)")
+	fs.StringVar(&o.palette, "palette", "", "color palette: default or colorblind (default $SKY_PALETTE or default)")
+	profileFlag(fs, o)
+}
+
+// profileFlag registers the flag selecting a configuration profile
+func profileFlag(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.profile, "profile", "", "use a profile of the configuration file (default $SKY_PROFILE)")
+}
+
+// weatherFlags registers the flags shared by the commands that fetch weather
+func weatherFlags(fs *flag.FlagSet, o *options) {
+	o.units.register(fs)
+	outputFlags(fs, o)
+	o.cache.register(fs)
+}
+
+// weatherCommand returns a command runner for a runWeather mode
+func weatherCommand(mode string) func(a *app, o *options, args []string) int {
+	return func(a *app, o *options, args []string) int {
+		return a.runWeather(mode, o, args)
+	}
+}
+
+// findCommand returns the command with the given name, or nil
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// flagSet returns the flag set of c, writing errors and help to w
+func (c *command) flagSet(w io.Writer, o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("sky "+c.name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	c.flags(fs, o)
+	fs.Usage = func() {
+		name := c.name
+		if name == defaultCommand {
+			name = "[" + name + "]"
+		}
+		fmt.Fprintf(w, "Usage: sky %s [flags] %s\n\n%s.\n", name, c.args, c.summary)
+		if hasFlags(fs) {
+			fmt.Fprintln(w, "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// hasFlags reports whether fs defines any flag
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// run executes sky with the given arguments and returns the exit code.
+// The first argument may name a command; otherwise current conditions are shown.
+func (a *app) run(args []string) int {
+	c := findCommand(defaultCommand)
+	if len(args) > 0 {
+		switch args[0] {
+		case "help", "-h", "-help", "--help":
+			return a.help(args[1:])
+		}
+		if named := findCommand(args[0]); named != nil {
+			c, args = named, args[1:]
+		}
+	}
+
+	var o options
+	positional, err := parseArgs(c.flagSet(a.stderr, &o), args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	return c.run(a, &o, positional)
+}
+
+// help prints the usage of sky, or of the command named in args
+func (a *app) help(args []string) int {
+	if len(args) > 0 {
+		c := findCommand(args[0])
+		if c == nil {
+			return a.usageError(fmt.Errorf("unknown command %q", args[0]))
+		}
+		c.flagSet(a.stdout, &options{}).Usage()
+		return exitOK
+	}
+
+	fmt.Fprint(a.stdout, "Usage: sky [command] [flags] [location]\n\n"+
+		"Show the weather for a location. Without a command, sky shows current\n"+
+		"conditions; without a location, it uses the configured default location\n"+
+		"or starts an interactive session.\n\n"+
+		"Commands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(a.stdout, "  %-10s  %s\n", c.name, c.summary)
+	}
+	fmt.Fprint(a.stdout, "\nRun 'sky help <command>' for the flags of a command.\n")
+	return exitOK
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_Help(t *testing.T) {
+	for _, args := range [][]string{{"help"}, {"-h"}, {"--help"}} {
+		ta := newTestApp("")
+
+		code := ta.run(args)
+
+		assert.Equal(t, exitOK, code, args)
+		assert.Contains(t, ta.stdout.String(), "Usage: sky [command] [flags] [location]")
+		for _, c := range commands {
+			assert.Contains(t, ta.stdout.String(), "  "+c.name+" ")
+		}
+	}
+}
+
+func TestRun_HelpCommand(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"help", "forecast"})
+
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, ta.stdout.String(), "Usage: sky forecast [flags] [location]\n\nShow a day-by-day forecast.\n\nFlags:\n")
+	assert.Contains(t, ta.stdout.String(), "-days int")
+	assert.NotContains(t, ta.stdout.String(), "-hours")
+}
+
+func TestRun_HelpUnknownCommand(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"help", "tomorrow"})
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, ta.stderr.String(), `unknown command "tomorrow"`)
+}
+
+func TestRun_CommandHelpFlag(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"search", "--help"})
+
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, ta.stderr.String(), "Usage: sky search [flags] [name]")
+	assert.Contains(t, ta.stderr.String(), "-count int")
+	assert.Empty(t, ta.stdout.String())
+}
+
+func TestRun_NowCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Explicit", []string{"now", "--detail", "New York"}},
+		{"Implicit", []string{"--detail", "New York"}},
+		{"Implicit with flag after the city", []string{"New York", "--detail"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Contains(t, ta.stdout.String(), "New York, US\nPartly cloudy")
+			assert.Contains(t, ta.stdout.String(), "Humidity:")
+		})
+	}
+}
+
+func TestRun_UnknownFlag(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"forecast", "--hours", "3", "New York"})
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, ta.stderr.String(), "flag provided but not defined: -hours\nUsage: sky forecast")
+	assert.Empty(t, ta.stdout.String())
+}
+
+func TestRun_Completion(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			"complete -F _sky sky",
+			"now|hourly|forecast|air|watch|tui|search|config|fav|completion",
+			`hourly) COMPREPLY=($(compgen -W "--cache`,
+			`fav) COMPREPLY=($(compgen -W "add list rm" -- "$cur")); return ;;`,
+		}},
+		{"zsh", []string{"compdef _sky sky", "(forecast) compadd -- --cache", "--days", "(config) compadd -- get set edit path; return ;;"}},
+		{"fish", []string{
+			"complete -c sky -n '__fish_use_subcommand' -a search -d 'List the places matching a name'",
+			"complete -c sky -n '__fish_seen_subcommand_from search' -l count -d 'number of candidates to list'",
+			"complete -c sky -n 'not __fish_seen_subcommand_from now hourly forecast air watch tui search config fav completion' -l detail",
+			"complete -c sky -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run([]string{"completion", tt.shell})
+
+			assert.Equal(t, exitOK, code, ta.stderr.String())
+			for _, want := range tt.want {
+				assert.Contains(t, ta.stdout.String(), want)
+			}
+		})
+	}
+}
+
+func TestRun_CompletionErrors(t *testing.T) {
+	for _, args := range [][]string{{"completion"}, {"completion", "tcsh"}} {
+		ta := newTestApp("")
+
+		code := ta.run(args)
+
+		assert.Equal(t, exitUsage, code, args)
+		assert.Contains(t, ta.stderr.String(), "bash, zsh, fish")
+	}
+}
+
+func TestFishQuote(t *testing.T) {
+	assert.Equal(t, `'it\'s a \\ test'`, fishQuote(`it's a \ test`))
+}
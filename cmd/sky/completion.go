@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completionShells are the shells sky completion writes scripts for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// runCompletion prints the completion script for the shell named in args
+func (a *app) runCompletion(o *options, args []string) int {
+	if len(args) != 1 {
+		return a.usageError(fmt.Errorf("completion needs a shell: %s", strings.Join(completionShells, ", ")))
+	}
+
+	switch args[0] {
+	case "bash":
+		writeBashCompletion(a.stdout)
+	case "zsh":
+		writeZshCompletion(a.stdout)
+	case "fish":
+		writeFishCompletion(a.stdout)
+	default:
+		return a.usageError(fmt.Errorf("unknown shell %q (want %s)", args[0], strings.Join(completionShells, ", ")))
+	}
+	return exitOK
+}
+
+// commandNames returns the names of all commands
+func commandNames() []string {
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.name
+	}
+	return names
+}
+
+// commandFlags returns the flags of c
+func commandFlags(c *command) []*flag.Flag {
+	var flags []*flag.Flag
+	c.flagSet(io.Discard, &options{}).VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f)
+	})
+	return flags
+}
+
+// flagWords returns the flags of c as they are typed, e.g. --detail
+func flagWords(c *command) string {
+	var words []string
+	for _, f := range commandFlags(c) {
+		words = append(words, "--"+f.Name)
+	}
+	return strings.Join(words, " ")
+}
+
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintf(w, `# bash completion for sky; load with: source <(sky completion bash)
+_sky() {
+	local cur=${COMP_WORDS[COMP_CWORD]} cmd=%s
+	if [[ $COMP_CWORD -gt 1 ]]; then
+		case ${COMP_WORDS[1]} in
+		%s) cmd=${COMP_WORDS[1]} ;;
+		esac
+	fi
+	if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+		COMPREPLY=($(compgen -W "%s" -- "$cur"))
+		return
+	fi
+	if [[ $COMP_CWORD -eq 2 && $cur != -* ]]; then
+		case $cmd in
+%s		esac
+	fi
+	[[ $cur == -* ]] || return
+	case $cmd in
+`, defaultCommand, strings.Join(commandNames(), "|"), strings.Join(commandNames(), " "), actionCases(func(name, words string) string {
+		return fmt.Sprintf("%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;", name, words)
+	}))
+	for _, c := range commands {
+		if words := flagWords(c); words != "" {
+			fmt.Fprintf(w, "\t%s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", c.name, words)
+		}
+	}
+	fmt.Fprint(w, "\tesac\n}\ncomplete -F _sky sky\n")
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprintf(w, `# zsh completion for sky; load with: source <(sky completion zsh)
+_sky() {
+	local cmd=%s
+	if (( CURRENT > 2 )); then
+		case $words[2] in
+		(%s) cmd=$words[2] ;;
+		esac
+	fi
+	if (( CURRENT == 2 )) && [[ $PREFIX != -* ]]; then
+		compadd -- %s
+		return
+	fi
+	if (( CURRENT == 3 )) && [[ $PREFIX != -* ]]; then
+		case $cmd in
+%s		esac
+	fi
+	[[ $PREFIX == -* ]] || return
+	case $cmd in
+`, defaultCommand, strings.Join(commandNames(), "|"), strings.Join(commandNames(), " "), actionCases(func(name, words string) string {
+		return fmt.Sprintf("(%s) compadd -- %s; return ;;", name, words)
+	}))
+	for _, c := range commands {
+		if words := flagWords(c); words != "" {
+			fmt.Fprintf(w, "\t(%s) compadd -- %s ;;\n", c.name, words)
+		}
+	}
+	fmt.Fprint(w, "\tesac\n}\ncompdef _sky sky\n")
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprint(w, "# fish completion for sky; load with: sky completion fish | source\ncomplete -c sky -f\n")
+	names := strings.Join(commandNames(), " ")
+	for _, c := range commands {
+		fmt.Fprintf(w, "complete -c sky -n '__fish_use_subcommand' -a %s -d %s\n", c.name, fishQuote(c.summary))
+	}
+	for _, c := range commands {
+		if len(c.actions) > 0 {
+			fmt.Fprintf(w, "complete -c sky -n '__fish_seen_subcommand_from %s' -a '%s'\n", c.name, strings.Join(c.actions, " "))
+		}
+	}
+
+	for _, c := range commands {
+		// Flags of the default command also apply before any command is typed
+		condition := fmt.Sprintf("__fish_seen_subcommand_from %s", c.name)
+		if c.name == defaultCommand {
+			condition = fmt.Sprintf("not __fish_seen_subcommand_from %s", names)
+		}
+		for _, f := range commandFlags(c) {
+			fmt.Fprintf(w, "complete -c sky -n '%s' -l %s -d %s\n", condition, f.Name, fishQuote(f.Usage))
+		}
+	}
+}
+
+// actionCases returns the shell case branches, written by branch, that
+// complete the actions of each command
+func actionCases(branch func(name, words string) string) string {
+	var b strings.Builder
+	for _, c := range commands {
+		if len(c.actions) > 0 {
+			b.WriteString("\t\t" + branch(c.name, strings.Join(c.actions, " ")) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// fishQuote quotes s as a single-quoted fish string
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/config"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
+)
+
+// setting is a configuration value. It is read from its environment
+// variable, else from the selected profile, else from the top of the
+// configuration file.
+type setting struct {
+	name string
+	env  string
+	def  string
+
+	// check validates a value before sky config set stores it
+	check func(value string) error
+}
+
+// settings are the configuration values sky reads, in the order shown by sky config
+var settings = []setting{
+	{"location", "SKY_LOCATION", "", nil},
+	{"units", "SKY_UNITS", "metric", func(v string) error {
+		_, err := api.UnitSystem(v)
+		return err
+	}},
+	{"format", "SKY_FORMAT", "text", func(v string) error {
+		_, err := jsonFormat(v, "")
+		return err
+	}},
+	{"color", "SKY_COLOR", "auto", func(v string) error {
+		_, err := ui.DetectColorMode(v, false, func(string) string { return "" })
+		return err
+	}},
+	{"palette", "SKY_PALETTE", "default", func(v string) error {
+		_, err := ui.ParsePalette(v)
+		return err
+	}},
+	{"language", "SKY_LANGUAGE", "en", nil},
+	{"timeout", "SKY_TIMEOUT", requestTimeout.String(), checkDuration},
+	{"cache", "SKY_CACHE", "0", func(v string) error {
+		_, err := strconv.ParseBool(v)
+		return err
+	}},
+	{"cache-ttl", "SKY_CACHE_TTL", "10m", checkDuration},
+	{"geocoding-url", "SKY_GEOCODING_URL", api.DefaultGeocodingURL, nil},
+	{"forecast-url", "SKY_FORECAST_URL", api.DefaultForecastURL, nil},
+	{"air-quality-url", "SKY_AIR_QUALITY_URL", api.DefaultAirQualityURL, nil},
+	{"reverse-geocoding-url", "SKY_REVERSE_GEOCODING_URL", "", nil},
+}
+
+// configActions are the subcommands of sky config
+var configActions = []string{"get", "set", "edit", "path"}
+
+func checkDuration(v string) error {
+	d, err := time.ParseDuration(v)
+	if err == nil && d <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	return err
+}
+
+// findSetting returns the setting with the given name, or nil
+func findSetting(name string) *setting {
+	for i := range settings {
+		if settings[i].name == name {
+			return &settings[i]
+		}
+	}
+	return nil
+}
+
+// loadConfig reads the configuration file and makes its settings, from
+// profile or $SKY_PROFILE when given, the defaults of the matching
+// SKY_* environment variables
+func (a *app) loadConfig(profile string) error {
+	path, file, err := a.readConfig()
+	if err != nil {
+		return err
+	}
+	for _, key := range file.Keys() {
+		if findSetting(key) == nil {
+			return fmt.Errorf("%s: unknown setting %q", path, key)
+		}
+	}
+
+	if profile == "" {
+		profile = a.getenv("SKY_PROFILE")
+	}
+	if profile != "" && !file.HasProfile(profile) {
+		return fmt.Errorf("unknown profile %q (add [profile.%s] to %s)", profile, profile, path)
+	}
+
+	// Wrap the environment, not an earlier configuration
+	if a.environ == nil {
+		a.environ = a.getenv
+	}
+	getenv := a.environ
+	a.getenv = func(key string) string {
+		if value := getenv(key); value != "" {
+			return value
+		}
+		for _, s := range settings {
+			if s.env == key {
+				value, _ := file.Get(profile, s.name)
+				return value
+			}
+		}
+		return ""
+	}
+	return nil
+}
+
+// readConfig returns the path of the configuration file and its contents
+func (a *app) readConfig() (string, *config.File, error) {
+	path, err := config.Path(a.getenv)
+	if err != nil {
+		return "", nil, err
+	}
+	file, err := config.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, file, nil
+}
+
+// runConfig shows or changes the configuration: without arguments it
+// lists the effective settings
+func (a *app) runConfig(o *options, args []string) int {
+	action := ""
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	switch action {
+	case "":
+		return a.configList(o, args)
+	case "get":
+		return a.configGet(o, args)
+	case "set":
+		return a.configSet(o, args)
+	case "edit":
+		return a.configEdit(args)
+	case "path":
+		path, err := config.Path(a.getenv)
+		if err != nil {
+			return a.fail(err)
+		}
+		fmt.Fprintln(a.stdout, path)
+		return exitOK
+	default:
+		// sky config <setting> is short for sky config get <setting>
+		return a.configGet(o, append([]string{action}, args...))
+	}
+}
+
+// configList prints every setting with its effective value
+func (a *app) configList(o *options, args []string) int {
+	if len(args) > 0 {
+		return a.usageError(fmt.Errorf("unexpected arguments: %s", strings.Join(args, " ")))
+	}
+	if err := a.loadConfig(o.profile); err != nil {
+		return a.fail(err)
+	}
+
+	for _, s := range settings {
+		fmt.Fprintf(a.stdout, "%s = %s\n", s.name, strconv.Quote(a.settingValue(s)))
+	}
+	return exitOK
+}
+
+// configGet prints the effective value of one setting
+func (a *app) configGet(o *options, args []string) int {
+	if len(args) != 1 {
+		return a.usageError(fmt.Errorf("usage: sky config get <setting>"))
+	}
+	s := findSetting(args[0])
+	if s == nil {
+		return a.usageError(fmt.Errorf("unknown setting %q", args[0]))
+	}
+	if err := a.loadConfig(o.profile); err != nil {
+		return a.fail(err)
+	}
+
+	fmt.Fprintln(a.stdout, a.settingValue(*s))
+	return exitOK
+}
+
+// settingValue returns the effective value of s, or its default
+func (a *app) settingValue(s setting) string {
+	if value := a.getenv(s.env); value != "" {
+		return value
+	}
+	return s.def
+}
+
+// configSet stores a setting in the configuration file, in the profile
+// given with --profile or at the top level
+func (a *app) configSet(o *options, args []string) int {
+	if len(args) != 2 {
+		return a.usageError(fmt.Errorf("usage: sky config set <setting> <value>"))
+	}
+	s := findSetting(args[0])
+	if s == nil {
+		return a.usageError(fmt.Errorf("unknown setting %q", args[0]))
+	}
+	if s.check != nil {
+		if err := s.check(args[1]); err != nil {
+			return a.usageError(fmt.Errorf("invalid %s %q: %w", s.name, args[1], err))
+		}
+	}
+
+	path, file, err := a.readConfig()
+	if err != nil {
+		return a.fail(err)
+	}
+	if err := file.Set(o.profile, s.name, args[1]); err != nil {
+		return a.usageError(err)
+	}
+	if err := file.Save(path); err != nil {
+		return a.fail(err)
+	}
+	return exitOK
+}
+
+// configEdit opens the configuration file in $VISUAL or $EDITOR and
+// checks it afterwards
+func (a *app) configEdit(args []string) int {
+	if len(args) > 0 {
+		return a.usageError(fmt.Errorf("unexpected arguments: %s", strings.Join(args, " ")))
+	}
+	path, err := config.Path(a.getenv)
+	if err != nil {
+		return a.fail(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return a.fail(fmt.Errorf("failed to create config directory: %w", err))
+	}
+
+	editor := a.getenv("VISUAL")
+	if editor == "" {
+		editor = a.getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor may be given with arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = a.stdin, a.stdout, a.stderr
+	if err := cmd.Run(); err != nil {
+		return a.fail(fmt.Errorf("failed to run editor: %w", err))
+	}
+
+	if err := a.loadConfig(""); err != nil {
+		return a.fail(err)
+	}
+	return exitOK
+}
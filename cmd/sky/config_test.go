@@ -0,0 +1,248 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// withConfig points ta at a configuration file holding text and returns its path
+func withConfig(t *testing.T, ta *testApp, text string) string {
+	dir := t.TempDir()
+	ta.env["XDG_CONFIG_HOME"] = dir
+	path := filepath.Join(dir, "sky", "config.toml")
+	if text != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(text), 0o644))
+	}
+	return path
+}
+
+func TestRun_Config(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["SKY_UNITS"] = "imperial"
+
+	code := ta.run([]string{"config"})
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "units = \"imperial\"\nformat = \"text\"\n")
+	assert.Contains(t, ta.stdout.String(), "forecast-url = \"https://api.open-meteo.com\"\n")
+}
+
+func TestRun_ConfigSetting(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+		err  string
+	}{
+		{"From environment", []string{"config", "cache-ttl"}, exitOK, "30m\n", ""},
+		{"Default", []string{"config", "palette"}, exitOK, "default\n", ""},
+		{"Unknown", []string{"config", "colour"}, exitUsage, "", `unknown setting "colour"`},
+		{"Too many", []string{"config", "units", "format"}, exitUsage, "", "usage: sky config get <setting>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.env["SKY_CACHE_TTL"] = "30m"
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.out, ta.stdout.String())
+			assert.Contains(t, ta.stderr.String(), tt.err)
+		})
+	}
+}
+
+func TestRun_ConfigFile(t *testing.T) {
+	ta := newTestApp("")
+	withConfig(t, ta, `location = "New York"
+units = "imperial"
+language = "de"
+
+[profile.work]
+units = "scientific"
+`)
+
+	code := ta.run(nil)
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.NotContains(t, ta.stdout.String(), "sky> ")
+	assert.Contains(t, ta.stdout.String(), "New York, US")
+	assert.Equal(t, api.ImperialUnits, ta.client.Units)
+	assert.Equal(t, "de", ta.client.Language)
+}
+
+func TestRun_ConfigProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		units   api.Units
+		wantErr string
+	}{
+		{"Top level", []string{"New York"}, nil, api.ImperialUnits, ""},
+		{"Profile flag", []string{"--profile", "work", "New York"}, nil, api.ScientificUnits, ""},
+		{"Profile from environment", []string{"New York"}, map[string]string{"SKY_PROFILE": "work"}, api.ScientificUnits, ""},
+		{"Environment wins", []string{"--profile", "work", "New York"}, map[string]string{"SKY_UNITS": "metric"}, api.MetricUnits, ""},
+		{"Flag wins", []string{"--profile", "work", "--units", "metric", "New York"}, nil, api.MetricUnits, ""},
+		{"Unknown profile", []string{"--profile", "home", "New York"}, nil, api.Units{}, `unknown profile "home"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			withConfig(t, ta, "units = \"imperial\"\n\n[profile.work]\nunits = \"scientific\"\n")
+			for k, v := range tt.env {
+				ta.env[k] = v
+			}
+
+			code := ta.run(tt.args)
+
+			if tt.wantErr != "" {
+				assert.Equal(t, exitFailure, code)
+				assert.Contains(t, ta.stderr.String(), tt.wantErr)
+				return
+			}
+			assert.Equal(t, exitOK, code, ta.stderr.String())
+			assert.Equal(t, tt.units, ta.client.Units)
+		})
+	}
+}
+
+func TestRun_ConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		code int
+		err  string
+	}{
+		{"Syntax", "units imperial\n", exitFailure, "config.toml: line 1: expected key = value"},
+		{"Unknown setting", "colour = \"always\"\n", exitFailure, `unknown setting "colour"`},
+		{"Invalid timeout", "timeout = \"soon\"\n", exitUsage, `invalid SKY_TIMEOUT "soon"`},
+		{"Invalid color", "color = \"sometimes\"\n", exitUsage, `unknown color setting "sometimes"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			withConfig(t, ta, tt.text)
+
+			code := ta.run([]string{"New York"})
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+			assert.Empty(t, ta.stdout.String())
+		})
+	}
+}
+
+func TestRun_ConfigColor(t *testing.T) {
+	ta := newTestApp("")
+	withConfig(t, ta, "color = \"always\"\n")
+
+	code := ta.run([]string{"New York"})
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "\x1b[")
+}
+
+func TestRun_ConfigSet(t *testing.T) {
+	ta := newTestApp("")
+	path := withConfig(t, ta, "# my settings\nunits = \"metric\"\n")
+
+	for _, args := range [][]string{
+		{"config", "set", "units", "imperial"},
+		{"config", "set", "location", "New York"},
+		{"config", "set", "--profile", "work", "timeout", "30s"},
+	} {
+		assert.Equal(t, exitOK, ta.run(args), ta.stderr.String())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# my settings\nunits = \"imperial\"\nlocation = \"New York\"\n\n[profile.work]\ntimeout = \"30s\"\n", string(data))
+
+	ta.stdout.Reset()
+	assert.Equal(t, exitOK, ta.run([]string{"config", "get", "--profile", "work", "timeout"}))
+	assert.Equal(t, exitOK, ta.run([]string{"config", "get", "timeout"}))
+	assert.Equal(t, "30s\n15s\n", ta.stdout.String())
+}
+
+func TestRun_ConfigSetErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"Missing value", []string{"config", "set", "units"}, "usage: sky config set <setting> <value>"},
+		{"Unknown setting", []string{"config", "set", "colour", "always"}, `unknown setting "colour"`},
+		{"Invalid units", []string{"config", "set", "units", "nautical"}, `invalid units "nautical"`},
+		{"Invalid duration", []string{"config", "set", "cache-ttl", "-5m"}, `invalid cache-ttl "-5m"`},
+		{"Invalid profile", []string{"config", "set", "--profile", "a b", "units", "metric"}, `invalid profile name "a b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			path := withConfig(t, ta, "")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+			assert.NoFileExists(t, path)
+		})
+	}
+}
+
+func TestRun_ConfigPath(t *testing.T) {
+	ta := newTestApp("")
+	path := withConfig(t, ta, "not valid\n")
+
+	code := ta.run([]string{"config", "path"})
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Equal(t, path+"\n", ta.stdout.String())
+}
+
+func TestRun_ConfigEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test editor is a shell script")
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		code   int
+		err    string
+	}{
+		{"Valid", `echo 'units = "imperial"' >> "$1"`, exitOK, ""},
+		{"Invalid", `echo 'units imperial' >> "$1"`, exitFailure, "line 1: expected key = value"},
+		{"Editor fails", "exit 1", exitFailure, "failed to run editor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			withConfig(t, ta, "")
+			editor := filepath.Join(t.TempDir(), "editor")
+			require.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\n"+tt.script+"\n"), 0o755))
+			ta.env["VISUAL"] = editor
+
+			code := ta.run([]string{"config", "edit"})
+
+			assert.Equal(t, tt.code, code, ta.stderr.String())
+			assert.Contains(t, ta.stderr.String(), tt.err)
+		})
+	}
+}
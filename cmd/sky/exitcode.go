@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+)
+
+// Exit codes returned by sky. Scripts can rely on these values.
+const (
+	exitOK       = 0 // success
+	exitFailure  = 1 // any failure not listed below
+	exitUsage    = 2 // invalid flags or arguments
+	exitNotFound = 3 // the location could not be geocoded or no favorite has the alias
+	exitAPI      = 4 // the service returned an error status or unreadable data
+	exitNetwork  = 5 // the service could not be reached
+	exitTimeout  = 6 // the request budget ran out
+
+	exitInterrupted = 130 // interrupted by Ctrl+C or SIGTERM, as shells report SIGINT
+)
+
+// errInterrupted replaces the errors of requests canceled by an interrupt
+var errInterrupted = errors.New("interrupted")
+
+// errorClass names the error class of an exit code in JSON errors
+func errorClass(code int) string {
+	switch code {
+	case exitUsage:
+		return "usage"
+	case exitNotFound:
+		return "not_found"
+	case exitAPI:
+		return "api"
+	case exitNetwork:
+		return "network"
+	case exitTimeout:
+		return "timeout"
+	case exitInterrupted:
+		return "canceled"
+	default:
+		return "failure"
+	}
+}
+
+// exitCode returns the exit code for an error class
+func exitCode(err error) int {
+	var apiErr *api.APIError
+	var netErr *api.NetworkError
+	var respErr *api.ResponseError
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errInterrupted), errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, api.ErrLocationNotFound), errors.Is(err, favorites.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, api.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.As(err, &netErr):
+		return exitNetwork
+	case errors.As(err, &apiErr), errors.As(err, &respErr):
+		return exitAPI
+	default:
+		return exitFailure
+	}
+}
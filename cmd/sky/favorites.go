@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/config"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
+)
+
+// favActions are the subcommands of sky fav
+var favActions = []string{"add", "list", "rm"}
+
+// runFav adds, lists or removes favorites
+func (a *app) runFav(o *options, args []string) int {
+	if err := a.loadConfig(o.profile); err != nil {
+		return a.fail(err)
+	}
+
+	action := ""
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	switch action {
+	case "add":
+		return a.favAdd(o, args)
+	case "list", "":
+		return a.favList(args)
+	case "rm":
+		return a.favRemove(args)
+	default:
+		return a.usageError(fmt.Errorf("unknown fav command %q (want %s)", action, strings.Join(favActions, ", ")))
+	}
+}
+
+// favAdd resolves a location and saves it under an alias
+func (a *app) favAdd(o *options, args []string) int {
+	if len(args) < 2 {
+		return a.usageError(fmt.Errorf("usage: sky fav add <alias> <location>"))
+	}
+	alias := strings.TrimPrefix(args[0], favorites.Prefix)
+	if !favorites.ValidAlias(alias) {
+		return a.usageError(fmt.Errorf("invalid alias %q (use letters, digits, - and _)", args[0]))
+	}
+	input := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	if _, err := a.connectClient(unitFlags{}); err != nil {
+		return a.usageError(err)
+	}
+	location, err := a.placeLocation(input, o.pick)
+	if err != nil {
+		return a.fail(err)
+	}
+
+	path, list, err := a.readFavorites()
+	if err != nil {
+		return a.fail(err)
+	}
+	if err := list.Add(alias, *location); err != nil {
+		return a.usageError(err)
+	}
+	if err := list.Save(path); err != nil {
+		return a.fail(err)
+	}
+
+	fmt.Fprintf(a.stdout, "Saved %s%s: %s (%.4f, %.4f)\n", favorites.Prefix, alias, ui.LocationLabel(location), location.Latitude, location.Longitude)
+	return exitOK
+}
+
+// favList prints the saved favorites
+func (a *app) favList(args []string) int {
+	if len(args) > 0 {
+		return a.usageError(fmt.Errorf("unexpected arguments: %s", strings.Join(args, " ")))
+	}
+	_, list, err := a.readFavorites()
+	if err != nil {
+		return a.fail(err)
+	}
+
+	if len(list.All()) == 0 {
+		fmt.Fprintln(a.stdout, "No favorites yet; save one with: sky fav add <alias> <location>")
+		return exitOK
+	}
+	fmt.Fprint(a.stdout, ui.FormatFavorites(list.All()))
+	return exitOK
+}
+
+// favRemove deletes the favorites named in args
+func (a *app) favRemove(args []string) int {
+	if len(args) == 0 {
+		return a.usageError(fmt.Errorf("usage: sky fav rm <alias>..."))
+	}
+	path, list, err := a.readFavorites()
+	if err != nil {
+		return a.fail(err)
+	}
+
+	for _, arg := range args {
+		alias := strings.TrimPrefix(arg, favorites.Prefix)
+		if !list.Remove(alias) {
+			return a.fail(fmt.Errorf("%w %s%s", favorites.ErrNotFound, favorites.Prefix, alias))
+		}
+	}
+	if err := list.Save(path); err != nil {
+		return a.fail(err)
+	}
+	return exitOK
+}
+
+// readFavorites returns the path of the favorites file and its contents
+func (a *app) readFavorites() (string, *favorites.List, error) {
+	dir, err := config.Dir(a.getenv)
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, favorites.FileName)
+	list, err := favorites.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, list, nil
+}
+
+// favoriteLocation returns the location saved under the alias written in
+// input, such as @home, or nil when input is not an alias
+func (a *app) favoriteLocation(input string) (*api.Location, error) {
+	alias, ok := favorites.ParseAlias(input)
+	if !ok {
+		return nil, nil
+	}
+	_, list, err := a.readFavorites()
+	if err != nil {
+		return nil, err
+	}
+
+	location, ok := list.Get(alias)
+	if !ok {
+		return nil, fmt.Errorf("%w %s%s", favorites.ErrNotFound, favorites.Prefix, alias)
+	}
+	return location, nil
+}
+
+// placeLocation resolves the location to save as a favorite. Coordinates are
+// labelled with the nearest place; names take the best match, or let the
+// user choose one when pick is set.
+func (a *app) placeLocation(input string, pick bool) (*api.Location, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.timeout)
+	defer cancel()
+
+	location, err := coordinateLocation(input)
+	if err != nil {
+		return nil, err
+	}
+	if location != nil {
+		if place, err := a.reverse.ReverseGeocode(ctx, location.Latitude, location.Longitude); err == nil {
+			return place, nil
+		}
+		return location, nil
+	}
+
+	locations, err := a.searchPlace(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if pick {
+		return a.chooseLocation(locations)
+	}
+	return &locations[0], nil
+}
+
+// searchPlace returns the places matching input, best match first. The name
+// may be followed by comma-separated qualifiers, each matching the country
+// code or an administrative area, as in "Springfield, Illinois, US".
+func (a *app) searchPlace(ctx context.Context, input string) ([]api.Location, error) {
+	parts := splitQualifiers(input)
+	if len(parts) == 1 {
+		return a.searcher.SearchLocations(ctx, input, api.DefaultSearchCount)
+	}
+
+	// Search widely so the qualifiers have candidates to choose from
+	candidates, err := a.searcher.SearchLocations(ctx, parts[0], api.MaxSearchCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []api.Location
+	for _, candidate := range candidates {
+		if matchesQualifiers(candidate, parts[1:]) {
+			locations = append(locations, candidate)
+		}
+	}
+	if len(locations) == 0 {
+		return nil, api.ErrLocationNotFound
+	}
+	return locations, nil
+}
+
+// splitQualifiers splits input at commas into a name and its qualifiers
+func splitQualifiers(input string) []string {
+	var parts []string
+	for _, part := range strings.Split(input, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return []string{input}
+	}
+	return parts
+}
+
+// matchesQualifiers reports whether every qualifier names the country code
+// or an administrative area of location
+func matchesQualifiers(location api.Location, qualifiers []string) bool {
+	for _, q := range qualifiers {
+		if !strings.EqualFold(q, location.Country) && !strings.EqualFold(q, location.Admin1) && !strings.EqualFold(q, location.Admin2) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+func TestRun_Favorites(t *testing.T) {
+	ta := newMultiTestApp()
+	withConfig(t, ta, "")
+
+	require.Equal(t, exitOK, ta.run([]string{"fav", "add", "home", "New", "York"}), ta.stderr.String())
+	require.Equal(t, exitOK, ta.run([]string{"fav", "add", "@tx", "Paris, Texas"}), ta.stderr.String())
+	assert.Equal(t, "Saved @home: New York, US (40.7143, -74.0060)\nSaved @tx: Paris, Texas, US (33.6609, -95.5555)\n", ta.stdout.String())
+
+	ta.stdout.Reset()
+	require.Equal(t, exitOK, ta.run([]string{"fav", "list"}))
+	assert.Equal(t, "@home  New York, US\n       40.7143, -74.0060\n@tx    Paris, Texas, US\n       33.6609, -95.5555\n", ta.stdout.String())
+
+	// Favorites are used as saved, without geocoding
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"Current conditions", []string{"@tx"}, []string{"Paris, US", "Temp: 21.3°C"}},
+		{"Forecast", []string{"forecast", "--days", "2", "@home"}, []string{"New York, US", "Next 2 days:"}},
+		{"Hourly", []string{"hourly", "--hours", "2", "@TX"}, []string{"Paris, US", "Next 2 hours:"}},
+		{"Several places", []string{"@home;", "Tokyo;", "@tx"}, []string{"New York, US", "Tokyo, JP", "Paris, US"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta.stdout.Reset()
+			ta.geocoder.queries = nil
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, exitOK, code, ta.stderr.String())
+			for _, want := range tt.want {
+				assert.Contains(t, ta.stdout.String(), want)
+			}
+			assert.NotContains(t, ta.geocoder.queries, "@home")
+			assert.NotContains(t, ta.geocoder.queries, "@tx")
+		})
+	}
+
+	ta.stdout.Reset()
+	require.Equal(t, exitOK, ta.run([]string{"fav", "rm", "@home", "tx"}), ta.stderr.String())
+	require.Equal(t, exitOK, ta.run([]string{"fav"}))
+	assert.Contains(t, ta.stdout.String(), "No favorites yet")
+}
+
+func TestRun_FavoritesAdd(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  api.Location
+	}{
+		{"Best match", []string{"Paris"}, "", api.Location{Name: "Paris", Country: "FR", Admin1: "Île-de-France", Latitude: 48.8534, Longitude: 2.3488}},
+		{"Qualified by area", []string{"Paris,", "Tennessee"}, "", api.Location{Name: "Paris", Country: "US", Admin1: "Tennessee", Latitude: 36.302, Longitude: -88.3267}},
+		{"Qualified by country", []string{"paris, us"}, "", api.Location{Name: "Paris", Country: "US", Admin1: "Texas", Latitude: 33.6609, Longitude: -95.5555}},
+		{"Picked", []string{"--pick", "Paris"}, "3\n", api.Location{Name: "Paris", Country: "US", Admin1: "Tennessee", Latitude: 36.302, Longitude: -88.3267}},
+		{"Coordinates", []string{"48.85,2.35"}, "", api.Location{Name: "Paris", Country: "FR", Latitude: 48.85, Longitude: 2.35}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp(tt.stdin)
+			ta.geocoder.candidates["paris"] = ta.geocoder.candidates["Paris"]
+			ta.reverse.place = &api.Location{Name: "Paris", Country: "FR"}
+			withConfig(t, ta, "")
+
+			code := ta.run(append([]string{"fav", "add", "p"}, tt.args...))
+			require.Equal(t, exitOK, code, ta.stderr.String())
+
+			location, err := ta.favoriteLocation("@p")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *location)
+		})
+	}
+}
+
+func TestRun_FavoritesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		err  string
+	}{
+		{"Unknown alias", []string{"@home"}, exitNotFound, "Error: no favorite named @home\nHint: run `sky fav list`"},
+		{"Unknown alias among several", []string{"-m", "New York", "@home"}, exitNotFound, "Error: @home: no favorite named @home"},
+		{"Missing location", []string{"fav", "add", "home"}, exitUsage, "usage: sky fav add <alias> <location>"},
+		{"Invalid alias", []string{"fav", "add", "my home", "Paris"}, exitUsage, `invalid alias "my home"`},
+		{"No match for qualifiers", []string{"fav", "add", "home", "Paris, Ohio"}, exitNotFound, "location not found"},
+		{"Remove unknown", []string{"fav", "rm", "home"}, exitNotFound, "no favorite named @home"},
+		{"Unknown action", []string{"fav", "show"}, exitUsage, `unknown fav command "show"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			withConfig(t, ta, "")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+		})
+	}
+}
+
+func TestMatchesQualifiers(t *testing.T) {
+	location := api.Location{Name: "Springfield", Country: "US", Admin1: "Illinois", Admin2: "Sangamon"}
+
+	assert.True(t, matchesQualifiers(location, nil))
+	assert.True(t, matchesQualifiers(location, []string{"illinois", "US"}))
+	assert.True(t, matchesQualifiers(location, []string{"Sangamon"}))
+	assert.False(t, matchesQualifiers(location, []string{"Missouri"}))
+	assert.False(t, matchesQualifiers(location, []string{"Illinois", "GB"}))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
+)
+
+// formatWeather formats current conditions in the selected format.
+// JSON always includes every detail; a template decides for itself.
+func (a *app) formatWeather(location *api.Location, weather *api.Weather, detail bool) (string, error) {
+	switch {
+	case a.template != nil:
+		return a.template.Execute(location, weather)
+	case a.json:
+		return ui.FormatWeatherJSON(location, weather)
+	case a.art && detail:
+		return ui.FormatWeatherArtDetail(location, weather, a.style), nil
+	case a.art:
+		return ui.FormatWeatherArt(location, weather, a.style), nil
+	case detail:
+		return ui.FormatWeatherDetailStyled(location, weather, a.style), nil
+	default:
+		return ui.FormatWeatherStyled(location, weather, a.style), nil
+	}
+}
+
+// loadTemplate parses the template given inline or in a file, if any
+func loadTemplate(text, file string, style ui.Style) (*ui.Template, error) {
+	if text != "" && file != "" {
+		return nil, fmt.Errorf("--template and --template-file cannot be used together")
+	}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		text = string(data)
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return ui.ParseTemplate(text, style)
+}
+
+// jsonFormat reports whether the output format, from the flag or else
+// the environment, is JSON
+func jsonFormat(flagValue, envValue string) (bool, error) {
+	format := flagValue
+	if format == "" {
+		format = envValue
+	}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return false, nil
+	case "json":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown output format %q (want text or json)", format)
+}
+
+// printError prints err to stderr, as a JSON object in JSON mode
+func (a *app) printError(err error, code int) {
+	if a.json {
+		fmt.Fprint(a.stderr, ui.FormatErrorJSON(err, errorClass(code), code))
+		return
+	}
+	fmt.Fprintln(a.stderr, ui.FormatError(err))
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// jsonLines decodes newline-delimited JSON output
+func jsonLines(t *testing.T, output string) []map[string]any {
+	t.Helper()
+
+	var docs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &doc), line)
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func TestRun_JSONFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		wantType string
+		wantDocs int
+	}{
+		{"Current", []string{"--format", "json", "New York"}, nil, "current", 1},
+		{"Environment", []string{"New York"}, map[string]string{"SKY_FORMAT": "json"}, "current", 1},
+		{"Hourly", []string{"hourly", "--hours", "3", "--format=json", "New York"}, nil, "hourly", 3},
+		{"Forecast", []string{"forecast", "--days", "2", "--format=json", "New York"}, nil, "daily", 2},
+		{"Search", []string{"search", "--format=json", "Paris"}, nil, "location", 3},
+		{"Air", []string{"air", "--format=json", "New York"}, nil, "air_quality", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			for k, v := range tt.env {
+				ta.env[k] = v
+			}
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			docs := jsonLines(t, ta.stdout.String())
+			require.Len(t, docs, tt.wantDocs)
+			for _, doc := range docs {
+				assert.Equal(t, tt.wantType, doc["type"])
+				assert.Equal(t, 1.0, doc["schema_version"])
+			}
+		})
+	}
+}
+
+func TestRun_JSONFormatFlagOverridesEnvironment(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["SKY_FORMAT"] = "json"
+
+	code := ta.run([]string{"--format", "text", "New York"})
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, ta.stdout.String(), "Temp: 21.3°C")
+}
+
+func TestRun_JSONWithAir(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--air", "--format", "json", "New York"})
+
+	assert.Equal(t, 0, code)
+	docs := jsonLines(t, ta.stdout.String())
+	require.Len(t, docs, 2)
+	assert.Equal(t, "current", docs[0]["type"])
+	assert.Equal(t, "air_quality", docs[1]["type"])
+}
+
+func TestRun_JSONMultiLocation(t *testing.T) {
+	ta := newMultiTestApp()
+
+	code := ta.run([]string{"--format", "json", "-m", "Tokyo", "Atlantis", "London"})
+
+	assert.Equal(t, 3, code)
+	docs := jsonLines(t, ta.stdout.String())
+	require.Len(t, docs, 2)
+	assert.Equal(t, "Tokyo", docs[0]["location"].(map[string]any)["name"])
+	assert.Equal(t, "London", docs[1]["location"].(map[string]any)["name"])
+
+	errs := jsonLines(t, ta.stderr.String())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Atlantis: location not found", errs[0]["error"].(map[string]any)["message"])
+	assert.Equal(t, "not_found", errs[0]["error"].(map[string]any)["class"])
+}
+
+func TestRun_JSONErrors(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--format", "json", "--units", "furlongs", "New York"})
+
+	assert.Equal(t, 2, code)
+	assert.Empty(t, ta.stdout.String())
+	errs := jsonLines(t, ta.stderr.String())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "usage", errs[0]["error"].(map[string]any)["class"])
+	assert.Equal(t, 2.0, errs[0]["error"].(map[string]any)["exit_code"])
+}
+
+func TestRun_JSONInterrupted(t *testing.T) {
+	ta := newTestApp("")
+	ta.weather.err = &api.NetworkError{What: "weather", Err: context.Canceled}
+
+	code := ta.run([]string{"--format", "json", "New York"})
+
+	assert.Equal(t, exitInterrupted, code)
+	errs := jsonLines(t, ta.stderr.String())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "canceled", errs[0]["error"].(map[string]any)["class"])
+	assert.Equal(t, "interrupted", errs[0]["error"].(map[string]any)["message"])
+	assert.Equal(t, 130.0, errs[0]["error"].(map[string]any)["exit_code"])
+}
+
+func TestRun_JSONUnencodable(t *testing.T) {
+	ta := newTestApp("")
+	ta.weather.weather.Temperature = math.NaN()
+
+	code := ta.run([]string{"--format", "json", "New York"})
+
+	// A value JSON cannot carry fails the command instead of crashing sky
+	assert.Equal(t, exitFailure, code)
+	assert.Empty(t, ta.stdout.String())
+	errs := jsonLines(t, ta.stderr.String())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0]["error"].(map[string]any)["message"], "failed to encode JSON")
+}
+
+func TestRun_JSONInteractiveKeepsStdoutClean(t *testing.T) {
+	ta := newTestApp("New York\n")
+
+	code := ta.run([]string{"--format", "json"})
+
+	// The prompt goes to stderr and the picker is skipped
+	assert.Equal(t, 0, code)
+	assert.Contains(t, ta.stderr.String(), "sky> ")
+	assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+	assert.Len(t, jsonLines(t, ta.stdout.String()), 1)
+}
+
+func TestRun_InvalidFormat(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--format", "yaml", "New York"})
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, ta.stderr.String(), `unknown output format "yaml"`)
+}
+
+func TestRun_Template(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--template", "{{.Location.Name}}: {{.Weather.Temperature | round}}{{.Units.Temp}} {{.Emoji}}", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, "New York: 21°C ⛅\n", ta.stdout.String())
+}
+
+func TestRun_TemplateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{.Header}} {{fixed 0 .Weather.Temperature}}{{.Units.Temp}}\n"), 0o644))
+	ta := newMultiTestApp()
+
+	code := ta.run([]string{"--template-file", path, "-m", "Tokyo", "London"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, "Tokyo, JP 21°C\nLondon, GB 21°C\n", ta.stdout.String())
+}
+
+func TestRun_TemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		contains string
+	}{
+		{"Parse error", []string{"--template", "{{.Location.Name", "New York"}, 2, "invalid template"},
+		{"Missing file", []string{"--template-file", "/nonexistent/sky.tmpl", "New York"}, 2, "failed to read template"},
+		{"Both", []string{"--template", "x", "--template-file", "y", "New York"}, 2, "cannot be used together"},
+		{"With JSON", []string{"--template", "x", "--format", "json", "New York"}, 2, "--template only works"},
+		{"Hourly", []string{"hourly", "--template", "x", "New York"}, 2, "flag provided but not defined: -template"},
+		{"Render error", []string{"--template", "{{.Nope}}", "New York"}, 1, "failed to render template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, ta.stderr.String(), tt.contains)
+			assert.Empty(t, ta.stdout.String())
+		})
+	}
+}
+
+func TestRun_Art(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--art", "--detail", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	out := ta.stdout.String()
+	assert.True(t, strings.HasPrefix(out, "New York, US\n    .-.        Partly cloudy\n"), out)
+	assert.Contains(t, out, "Humidity: 0%")
+	assert.NotContains(t, out, "⛅")
+}
+
+func TestRun_ArtErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"With JSON", []string{"--art", "--format", "json", "New York"}, "--art only works"},
+		{"With template", []string{"--art", "--template", "x", "New York"}, "--art only works"},
+		{"Forecast", []string{"forecast", "--art", "New York"}, "flag provided but not defined: -art"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, ta.stderr.String(), tt.want)
+		})
+	}
+}
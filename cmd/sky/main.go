@@ -3,61 +3,648 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/geo"
+	"github.com/kakkoiirus/sky-cli/internal/lineedit"
 	"github.com/kakkoiirus/sky-cli/internal/ui"
 )
 
+// requestTimeout bounds the API calls of a single invocation unless
+// SKY_TIMEOUT or the timeout setting says otherwise
+const requestTimeout = 15 * time.Second
+
+// services are the API providers used by a sky invocation
+type services struct {
+	geocoder api.Geocoder
+	searcher api.LocationSearcher
+	reverse  api.ReverseGeocoder
+	weather  api.WeatherProvider
+	forecast api.ForecastProvider
+	air      api.AirQualityProvider
+}
+
+// app holds the dependencies of a single sky invocation
+type app struct {
+	// ctx is the root context of the invocation, canceled by Ctrl+C or
+	// SIGTERM; every request and long-running mode derives from it
+	ctx context.Context
+
+	// connect returns the providers backed by the configured client
+	connect func(client *api.Client) services
+
+	// getenv reads settings from the environment and, once loadConfig has
+	// run, from the configuration file; environ is the environment alone
+	getenv  func(key string) string
+	environ func(key string) string
+
+	// terminal reports whether stdout is a terminal, enabling color
+	terminal bool
+
+	// width and height return the size of the terminal, or 0 when unknown
+	width  func() int
+	height func() int
+
+	// resized registers c to be notified when the terminal is resized
+	resized func(c chan<- os.Signal)
+
+	// style colors text output; see colorStyle
+	style ui.Style
+
+	// json selects JSON output, including errors on stderr
+	json bool
+
+	// template renders current conditions instead of the built-in formats
+	template *ui.Template
+
+	// art draws current conditions next to ASCII art instead of emoji
+	art bool
+
+	// chart draws forecasts as charts instead of tables
+	chart bool
+
+	// timeout bounds the API calls of the invocation; see requestTimeout
+	timeout time.Duration
+
+	// now and after tell the time and wait between the updates of watch mode
+	now   func() time.Time
+	after func(d time.Duration) <-chan time.Time
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// input reads lines from stdin; created on first use
+	input *bufio.Scanner
+
+	// rawInput switches stdin to raw mode for line editing; nil when stdin
+	// or stdout is not a terminal. It fails where raw mode is unsupported.
+	rawInput func() (restore func(), err error)
+
+	// editor reads the lines of an interactive session on a terminal
+	editor *lineedit.Editor
+
+	// recent are the places found during an interactive session, most recent first
+	recent []api.Location
+
+	services
+}
+
 func main() {
-	var cityName string
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	// Stop catching signals after the first, so a second one kills sky
+	context.AfterFunc(ctx, stop)
+	a := &app{
+		ctx: ctx,
+		connect: func(client *api.Client) services {
+			return services{
+				geocoder: client,
+				searcher: client,
+				reverse:  client,
+				weather:  client,
+				forecast: client,
+				air:      client,
+			}
+		},
+		getenv:   os.Getenv,
+		terminal: isTerminal(os.Stdout),
+		width:    func() int { return terminalWidth(os.Stdout) },
+		height:   func() int { return terminalHeight(os.Stdout) },
+		resized:  notifyResize,
+		now:      time.Now,
+		after:    time.After,
+		stdin:    os.Stdin,
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+	}
+	if a.terminal && isTerminal(os.Stdin) {
+		a.rawInput = func() (func(), error) { return makeRaw(os.Stdin) }
+	}
+	code := a.run(os.Args[1:])
+	stop()
+	os.Exit(code)
+}
 
-	// Check if city name is provided as argument
-	if len(os.Args) > 1 {
-		cityName = strings.Join(os.Args[1:], " ")
-	} else {
-		// Interactive mode
-		fmt.Print("Enter city name: ")
-		scanner := bufio.NewScanner(os.Stdin)
-		if !scanner.Scan() {
-			fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Errorf("failed to read input")))
-			os.Exit(1)
-			return
+// runWeather looks up a location and shows the weather there in the given
+// mode: now, hourly, forecast, air, watch or search
+func (a *app) runWeather(mode string, o *options, positional []string) int {
+	if err := a.loadConfig(o.profile); err != nil {
+		return a.fail(err)
+	}
+
+	var err error
+	a.art, a.chart = o.art, o.chart
+	if a.json, err = jsonFormat(o.format, a.getenv("SKY_FORMAT")); err != nil {
+		return a.usageError(err)
+	}
+	if a.style, err = a.colorStyle(o.color, o.palette); err != nil {
+		return a.usageError(err)
+	}
+	if a.template, err = loadTemplate(o.templateText, o.templateFile, a.style); err != nil {
+		return a.usageError(err)
+	}
+	if a.template != nil && (a.json || o.air) {
+		return a.usageError(fmt.Errorf("--template only works for current conditions in text format"))
+	}
+	if a.art && (a.json || a.template != nil) {
+		return a.usageError(fmt.Errorf("--art only works for current conditions in text format"))
+	}
+	if a.chart && a.json {
+		return a.usageError(fmt.Errorf("--chart only works in text format"))
+	}
+	// The API checks the lengths too, but bad flags are usage errors
+	switch {
+	case mode == "hourly" && (o.hours < 1 || o.hours > api.MaxForecastHours):
+		return a.usageError(fmt.Errorf("--hours must be between 1 and %d", api.MaxForecastHours))
+	case mode == "forecast" && (o.days < 1 || o.days > api.MaxForecastDays):
+		return a.usageError(fmt.Errorf("--days must be between 1 and %d", api.MaxForecastDays))
+	}
+
+	if err := a.connectServices(mode, o); err != nil {
+		return a.usageError(err)
+	}
+
+	// Without a location, use the configured default or start a session
+	if len(positional) == 0 && mode != "search" {
+		location := a.getenv("SKY_LOCATION")
+		switch {
+		case location != "":
+			positional = []string{location}
+		case mode == "watch":
+			return a.usageError(fmt.Errorf("sky watch needs a location or a configured default location"))
+		case mode == "tui":
+			// The dashboard lists the favorites
+		default:
+			return a.runREPL(mode, o)
+		}
+	}
+
+	switch mode {
+	case "search":
+		cityName, err := a.readCityName(positional)
+		if err != nil {
+			return a.fail(err)
+		}
+		return a.showSearch(cityName, o.count)
+	case "watch":
+		return a.runWatch(o, splitLocations(strings.Join(positional, " ")))
+	case "tui":
+		return a.runTUI(o, splitLocations(strings.Join(positional, " ")))
+	}
+
+	// Several locations are given with -m or separated by ";"
+	inputs := splitLocations(strings.Join(positional, " "))
+	if o.multi {
+		inputs = nil
+		for _, arg := range positional {
+			inputs = append(inputs, splitLocations(arg)...)
+		}
+	}
+	return a.show(mode, o, inputs, false)
+}
+
+// connectServices connects the providers for mode, putting the cache in
+// front of them when it is enabled
+func (a *app) connectServices(mode string, o *options) error {
+	client, err := a.connectClient(o.units)
+	if err != nil {
+		return err
+	}
+	if o.cache.offline && (mode != "now" || o.air) {
+		return fmt.Errorf("--offline only works for current conditions")
+	}
+	if mode == "watch" || mode == "tui" {
+		// Cached conditions would hide both new readings and failures
+		return nil
+	}
+	return o.cache.wrap(&a.services, client.Units, a.getenv)
+}
+
+// show looks up the places in inputs and shows the weather there in mode.
+// Interactive input lets the user choose among places with the same name.
+func (a *app) show(mode string, o *options, inputs []string, interactive bool) int {
+	if len(inputs) > 1 {
+		if mode != "now" || o.air {
+			return a.usageError(fmt.Errorf("multiple locations only work for current conditions"))
+		}
+		ctx, cancel := context.WithTimeout(a.ctx, a.timeout)
+		defer cancel()
+		return a.showMulti(ctx, inputs, o.detail)
+	}
+	if len(inputs) == 0 {
+		return a.fail(fmt.Errorf("city name cannot be empty"))
+	}
+	cityName := inputs[0]
+
+	// Favorites, places found earlier and coordinates go straight to the weather API
+	location, err := a.knownLocation(cityName)
+	if err != nil {
+		return a.fail(err)
+	}
+	known := location != nil
+	if !known {
+		if location, err = coordinateLocation(cityName); err != nil {
+			return a.fail(err)
+		}
+	}
+
+	// Let the user disambiguate before the request budget starts;
+	// offline there is nothing to search, so the cached location is used
+	if location == nil && interactive && !o.cache.offline && !a.json {
+		if location, err = a.pickLocation(cityName); err != nil {
+			return a.fail(err)
+		}
+	}
+
+	// Create context with timeout for API calls
+	ctx, cancel := context.WithTimeout(a.ctx, a.timeout)
+	defer cancel()
+
+	// Get location; known places are stored resolved
+	switch {
+	case location == nil:
+		if location, err = a.geocoder.GetLocation(ctx, cityName); err != nil {
+			return a.fail(err)
+		}
+	case !known:
+		// Label coordinates with the nearest named place; keep them raw otherwise
+		if place, err := a.reverse.ReverseGeocode(ctx, location.Latitude, location.Longitude); err == nil {
+			location = place
+		}
+	}
+
+	a.remember(location)
+
+	switch mode {
+	case "hourly":
+		return a.showHourly(ctx, location, o.hours)
+	case "forecast":
+		return a.showDaily(ctx, location, o.days)
+	case "air":
+		return a.showAirQuality(ctx, location)
+	default:
+		if code := a.showWeather(ctx, location, o.detail); code != 0 || !o.air {
+			return code
+		}
+		if !a.json {
+			fmt.Fprintln(a.stdout)
+		}
+		return a.showAirQuality(ctx, location)
+	}
+}
+
+// connectClient creates an API client from the settings and the unit flags
+// and connects the providers to it
+func (a *app) connectClient(units unitFlags) (*api.Client, error) {
+	var err error
+	a.timeout = requestTimeout
+	if env := a.getenv("SKY_TIMEOUT"); env != "" {
+		if a.timeout, err = time.ParseDuration(env); err != nil || a.timeout <= 0 {
+			return nil, fmt.Errorf("invalid SKY_TIMEOUT %q (want a duration such as 15s)", env)
+		}
+	}
+
+	client := api.NewClient()
+	if client.Units, err = units.resolve(a.getenv("SKY_UNITS")); err != nil {
+		return nil, err
+	}
+	client.Language = a.getenv("SKY_LANGUAGE")
+
+	// Allow pointing sky at a self-hosted Open-Meteo instance
+	if u := a.getenv("SKY_GEOCODING_URL"); u != "" {
+		client.GeocodingURL = u
+	}
+	if u := a.getenv("SKY_FORECAST_URL"); u != "" {
+		client.ForecastURL = u
+	}
+	if u := a.getenv("SKY_AIR_QUALITY_URL"); u != "" {
+		client.AirQualityURL = u
+	}
+	if u := a.getenv("SKY_REVERSE_GEOCODING_URL"); u != "" {
+		client.ReverseGeocodingURL = u
+	}
+	a.services = a.connect(client)
+	return client, nil
+}
+
+// parseArgs parses flags that may appear before, between or after positional arguments.
+// Negative numbers such as -33.86,151.21 are positional, not flags.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if len(args) > 0 && isNegativeNumber(args[0]) {
+			positional = append(positional, args[0])
+			args = args[1:]
+			continue
+		}
+
+		// Stop the flag parser before the next negative number
+		end := len(args)
+		for i, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if isNegativeNumber(arg) {
+				end = i
+				break
+			}
+		}
+		if err := fs.Parse(args[:end]); err != nil {
+			return nil, err
+		}
+		args = append(append([]string{}, fs.Args()...), args[end:]...)
+
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if isNegativeNumber(args[0]) {
+			continue
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// isNegativeNumber reports whether arg starts like a negative coordinate
+func isNegativeNumber(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && (arg[1] >= '0' && arg[1] <= '9' || arg[1] == '.')
+}
+
+// coordinateLocation returns a location for input written as coordinates,
+// or nil when input is a place name
+func coordinateLocation(input string) (*api.Location, error) {
+	point, err := geo.Parse(input)
+	if errors.Is(err, geo.ErrNotCoordinates) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Location{
+		Name:      point.String(),
+		Latitude:  point.Latitude,
+		Longitude: point.Longitude,
+	}, nil
+}
+
+// readCityName joins the positional arguments into a city name,
+// prompting for one when none were given
+func (a *app) readCityName(args []string) (string, error) {
+	cityName := strings.Join(args, " ")
+	if len(args) == 0 {
+		var err error
+		if cityName, err = a.readLine("Enter city name: "); err != nil {
+			return "", err
 		}
-		cityName = scanner.Text()
 	}
 
-	// Trim whitespace
 	cityName = strings.TrimSpace(cityName)
 	if cityName == "" {
-		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Errorf("city name cannot be empty")))
-		os.Exit(1)
-		return
+		return "", fmt.Errorf("city name cannot be empty")
 	}
+	return cityName, nil
+}
 
-	// Create context with timeout for API calls
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+// readLine prints prompt and reads one line from stdin, with the line
+// editor when the session has one
+func (a *app) readLine(prompt string) (string, error) {
+	if a.editor != nil {
+		line, err := a.editor.ReadLine(a.ctx, prompt)
+		if a.ctx.Err() != nil {
+			return "", errInterrupted
+		}
+		return line, err
+	}
+	if a.input == nil {
+		a.input = bufio.NewScanner(a.stdin)
+	}
+
+	// Keep JSON output on stdout parseable
+	if a.json {
+		fmt.Fprint(a.stderr, prompt)
+	} else {
+		fmt.Fprint(a.stdout, prompt)
+	}
+
+	// Scan in the background so that an interrupt ends the wait
+	scanned := make(chan bool, 1)
+	go func() { scanned <- a.input.Scan() }()
+	select {
+	case <-a.ctx.Done():
+		return "", errInterrupted
+	case ok := <-scanned:
+		if !ok {
+			return "", fmt.Errorf("failed to read input")
+		}
+		return a.input.Text(), nil
+	}
+}
+
+// pickLocation lists the candidates for cityName, which may be narrowed as
+// described in searchPlace, and lets the user choose one.
+// An empty answer selects the best match.
+func (a *app) pickLocation(cityName string) (*api.Location, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.timeout)
 	defer cancel()
 
-	// Get location
-	location, err := api.GetLocation(ctx, cityName)
+	locations, err := a.searchPlace(ctx, cityName)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, ui.FormatError(err))
-		os.Exit(1)
-		return
+		return nil, err
+	}
+	return a.chooseLocation(locations)
+}
+
+// chooseLocation lists locations and lets the user choose one.
+// An empty answer selects the first.
+func (a *app) chooseLocation(locations []api.Location) (*api.Location, error) {
+	if len(locations) == 1 {
+		return &locations[0], nil
 	}
 
-	// Get weather
-	weather, err := api.GetWeather(ctx, location.Latitude, location.Longitude)
+	fmt.Fprint(a.stdout, ui.FormatLocations(locations))
+	for {
+		answer, err := a.readLine(fmt.Sprintf("Choose a location [1-%d]: ", len(locations)))
+		if err != nil {
+			return nil, err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return &locations[0], nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(locations) {
+			return &locations[n-1], nil
+		}
+		fmt.Fprintf(a.stdout, "Please enter a number between 1 and %d\n", len(locations))
+	}
+}
+
+// showSearch prints the candidate locations matching cityName
+func (a *app) showSearch(cityName string, count int) int {
+	ctx, cancel := context.WithTimeout(a.ctx, a.timeout)
+	defer cancel()
+
+	locations, err := a.searcher.SearchLocations(ctx, cityName, count)
+	if err != nil {
+		return a.fail(err)
+	}
+
+	out := ui.FormatLocations(locations)
+	if a.json {
+		if out, err = ui.FormatLocationsJSON(locations); err != nil {
+			return a.fail(err)
+		}
+	}
+	fmt.Fprint(a.stdout, out)
+	return 0
+}
+
+// showWeather prints the current conditions at location
+func (a *app) showWeather(ctx context.Context, location *api.Location, detail bool) int {
+	weather, err := a.weather.GetWeather(ctx, location.Latitude, location.Longitude)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, ui.FormatError(err))
-		os.Exit(1)
-		return
+		return a.fail(err)
 	}
 
-	// Display result
-	fmt.Print(ui.FormatWeather(location, weather))
+	out, err := a.formatWeather(location, weather, detail)
+	if err != nil {
+		return a.fail(err)
+	}
+	fmt.Fprint(a.stdout, out)
+	return 0
+}
+
+// showHourly prints the forecast for the next hours at location
+func (a *app) showHourly(ctx context.Context, location *api.Location, hours int) int {
+	forecast, err := a.forecast.GetHourlyForecast(ctx, location.Latitude, location.Longitude, hours)
+	if err != nil {
+		return a.fail(err)
+	}
+
+	var out string
+	switch {
+	case a.json:
+		out, err = ui.FormatHourlyJSON(location, forecast)
+	case a.chart:
+		out = ui.FormatHourlyChart(location, forecast, a.chartWidth(), a.style)
+	default:
+		out = ui.FormatHourlyForecastStyled(location, forecast, a.style)
+	}
+	if err != nil {
+		return a.fail(err)
+	}
+	fmt.Fprint(a.stdout, out)
+	return 0
+}
+
+// showDaily prints the forecast for the next days at location
+func (a *app) showDaily(ctx context.Context, location *api.Location, days int) int {
+	forecast, err := a.forecast.GetDailyForecast(ctx, location.Latitude, location.Longitude, days)
+	if err != nil {
+		return a.fail(err)
+	}
+
+	var out string
+	switch {
+	case a.json:
+		out, err = ui.FormatDailyJSON(location, forecast)
+	case a.chart:
+		out = ui.FormatDailyChart(location, forecast, a.chartWidth(), a.style)
+	default:
+		out = ui.FormatDailyForecastStyled(location, forecast, a.style)
+	}
+	if err != nil {
+		return a.fail(err)
+	}
+	fmt.Fprint(a.stdout, out)
+	return 0
+}
+
+// showAirQuality prints the current air quality at location
+func (a *app) showAirQuality(ctx context.Context, location *api.Location) int {
+	aq, err := a.air.GetAirQuality(ctx, location.Latitude, location.Longitude)
+	if err != nil {
+		return a.fail(err)
+	}
+
+	out := ui.FormatAirQuality(location, aq, a.style)
+	if a.json {
+		if out, err = ui.FormatAirQualityJSON(location, aq); err != nil {
+			return a.fail(err)
+		}
+	}
+	fmt.Fprint(a.stdout, out)
+	return 0
+}
+
+// chartWidth returns the width available to charts: $COLUMNS, else the
+// width of the terminal, else ui.DefaultChartWidth
+func (a *app) chartWidth() int {
+	if n, err := strconv.Atoi(a.getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if a.width != nil {
+		if n := a.width(); n > 0 {
+			return n
+		}
+	}
+	return ui.DefaultChartWidth
+}
+
+// colorStyle selects the output colors from the --color and --palette flags
+// or their settings, the terminal and the environment (see https://no-color.org).
+// JSON output is never colored.
+func (a *app) colorStyle(setting, palette string) (ui.Style, error) {
+	if setting == "" {
+		setting = a.getenv("SKY_COLOR")
+	}
+	mode, err := ui.DetectColorMode(setting, a.terminal, a.getenv)
+	if err != nil {
+		return ui.Style{}, err
+	}
+	if palette == "" {
+		palette = a.getenv("SKY_PALETTE")
+	}
+	p, err := ui.ParsePalette(palette)
+	if err != nil {
+		return ui.Style{}, err
+	}
+	if a.json {
+		mode = ui.NoColor
+	}
+	return ui.Style{Mode: mode, Palette: p}, nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// fail prints err to stderr and returns the exit code for its class
+func (a *app) fail(err error) int {
+	code := exitCode(err)
+	if code == exitInterrupted {
+		// "failed to fetch weather: context canceled" says less than this
+		err = errInterrupted
+	}
+	a.printError(err, code)
+	return code
+}
+
+// usageError prints err to stderr and returns the usage exit code
+func (a *app) usageError(err error) int {
+	a.printError(err, exitUsage)
+	return exitUsage
 }
@@ -2,12 +2,19 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -314,3 +321,763 @@ func TestMain_FlowValidation(t *testing.T) {
 		assert.True(t, isEmpty, "Empty city name should trigger error path")
 	})
 }
+
+// fakeGeocoder is an in-memory api.Geocoder and api.LocationSearcher
+type fakeGeocoder struct {
+	locations  map[string]*api.Location
+	candidates map[string][]api.Location
+	queries    []string
+	mu         sync.Mutex
+}
+
+func (f *fakeGeocoder) GetLocation(ctx context.Context, city string) (*api.Location, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, city)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if location, ok := f.locations[city]; ok {
+		return location, nil
+	}
+	return nil, api.ErrLocationNotFound
+}
+
+func (f *fakeGeocoder) SearchLocations(ctx context.Context, name string, count int) ([]api.Location, error) {
+	f.queries = append(f.queries, name)
+	if candidates, ok := f.candidates[name]; ok {
+		if len(candidates) > count {
+			candidates = candidates[:count]
+		}
+		return candidates, nil
+	}
+	if location, ok := f.locations[name]; ok {
+		return []api.Location{*location}, nil
+	}
+	return nil, api.ErrLocationNotFound
+}
+
+// fakeAir is an in-memory api.AirQualityProvider
+type fakeAir struct {
+	calls int
+}
+
+func (f *fakeAir) GetAirQuality(ctx context.Context, lat, lon float64) (*api.AirQuality, error) {
+	f.calls++
+	return &api.AirQuality{USAQI: 42, EuropeanAQI: 25, PM25: 8.1}, nil
+}
+
+// fakeReverse is an api.ReverseGeocoder that knows a single place
+type fakeReverse struct {
+	place *api.Location
+}
+
+func (f *fakeReverse) ReverseGeocode(ctx context.Context, lat, lon float64) (*api.Location, error) {
+	if f.place == nil {
+		return nil, errors.New("no populated place nearby")
+	}
+	location := *f.place
+	location.Latitude, location.Longitude = lat, lon
+	return &location, nil
+}
+
+// fakeWeather is an in-memory api.WeatherProvider and api.BatchWeatherProvider
+type fakeWeather struct {
+	weather *api.Weather
+	err     error
+	lat     float64
+	lon     float64
+	calls   int
+	batches int
+
+	// failAt makes requests for a latitude fail
+	failAt map[float64]error
+	mu     sync.Mutex
+}
+
+func (f *fakeWeather) GetWeather(ctx context.Context, lat, lon float64) (*api.Weather, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.lat, f.lon = lat, lon
+	if err := f.failAt[lat]; err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.weather, nil
+}
+
+func (f *fakeWeather) GetWeatherBatch(ctx context.Context, points []api.Coordinates) ([]*api.Weather, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches++
+	weathers := make([]*api.Weather, len(points))
+	for i, p := range points {
+		if err := f.failAt[p.Latitude]; err != nil {
+			return nil, err
+		}
+		weathers[i] = f.weather
+	}
+	return weathers, nil
+}
+
+// fakeForecast is an in-memory api.ForecastProvider
+type fakeForecast struct {
+	hours int
+	days  int
+	mu    sync.Mutex
+}
+
+func (f *fakeForecast) GetHourlyForecast(ctx context.Context, lat, lon float64, hours int) (*api.HourlyForecast, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hours = hours
+	forecast := &api.HourlyForecast{}
+	for i := 0; i < hours; i++ {
+		forecast.Hours = append(forecast.Hours, api.HourlyWeather{
+			Time:            time.Date(2025, 3, 1, i, 0, 0, 0, time.UTC),
+			Temperature:     float64(i),
+			WeatherCodeDesc: "Clear",
+		})
+	}
+	return forecast, nil
+}
+
+func (f *fakeForecast) GetDailyForecast(ctx context.Context, lat, lon float64, days int) (*api.DailyForecast, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.days = days
+	forecast := &api.DailyForecast{}
+	for i := 0; i < days; i++ {
+		forecast.Days = append(forecast.Days, api.DailyWeather{
+			Date:            time.Date(2025, 3, 1+i, 0, 0, 0, 0, time.UTC),
+			TemperatureMax:  float64(10 + i),
+			WeatherCodeDesc: "Clear",
+		})
+	}
+	return forecast, nil
+}
+
+// testApp is an app wired to in-memory providers
+type testApp struct {
+	*app
+	geocoder *fakeGeocoder
+	reverse  *fakeReverse
+	weather  *fakeWeather
+	forecast *fakeForecast
+	air      *fakeAir
+	env      map[string]string
+	client   *api.Client
+	stdout   *bytes.Buffer
+	stderr   *bytes.Buffer
+}
+
+func newTestApp(stdin string) *testApp {
+	ta := &testApp{
+		geocoder: &fakeGeocoder{
+			locations: map[string]*api.Location{
+				"New York": {Name: "New York", Country: "US", Latitude: 40.7143, Longitude: -74.006},
+			},
+			candidates: map[string][]api.Location{
+				"Paris": {
+					{Name: "Paris", Country: "FR", Admin1: "Île-de-France", Latitude: 48.8534, Longitude: 2.3488},
+					{Name: "Paris", Country: "US", Admin1: "Texas", Latitude: 33.6609, Longitude: -95.5555},
+					{Name: "Paris", Country: "US", Admin1: "Tennessee", Latitude: 36.302, Longitude: -88.3267},
+				},
+			},
+		},
+		weather: &fakeWeather{weather: &api.Weather{
+			Temperature:     21.3,
+			ApparentTemp:    20.1,
+			WeatherCode:     2,
+			WeatherCodeDesc: "Partly cloudy",
+		}},
+		reverse:  &fakeReverse{},
+		forecast: &fakeForecast{},
+		air:      &fakeAir{},
+		// Never touch the configuration or history of the user running the tests
+		env:    map[string]string{"XDG_CONFIG_HOME": "/nonexistent", "XDG_STATE_HOME": "/nonexistent"},
+		stdout: &bytes.Buffer{},
+		stderr: &bytes.Buffer{},
+	}
+	ta.app = &app{
+		ctx: context.Background(),
+		connect: func(client *api.Client) services {
+			ta.client = client
+			return services{
+				geocoder: ta.geocoder,
+				searcher: ta.geocoder,
+				reverse:  ta.reverse,
+				weather:  ta.weather,
+				forecast: ta.forecast,
+				air:      ta.air,
+			}
+		},
+		getenv: func(key string) string { return ta.env[key] },
+		stdin:  strings.NewReader(stdin),
+		stdout: ta.stdout,
+		stderr: ta.stderr,
+	}
+	return ta
+}
+
+func TestRun_CommandLineMode(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"New", "York"})
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "New York, US")
+	assert.Contains(t, ta.stdout.String(), "Temp: 21.3°C")
+}
+
+func TestRun_InteractiveMode(t *testing.T) {
+	ta := newTestApp("  New York  \n")
+
+	code := ta.run(nil)
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, ta.stdout.String(), "sky> ")
+	assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		weather  error
+		contains string
+		code     int
+	}{
+		{"Empty search input", []string{"search"}, "   \n", nil, "city name cannot be empty", 1},
+		{"Closed stdin", []string{"search"}, "", nil, "failed to read input", 1},
+		{"Unknown city", []string{"Atlantis"}, "", nil, "location not found", 3},
+		{"Weather failure", []string{"New York"}, "", &api.APIError{StatusCode: 500}, "API returned status 500", 4},
+		{"Network failure", []string{"New York"}, "", &api.NetworkError{What: "weather", Err: errors.New("connection refused")}, "failed to fetch weather", 5},
+		{"Timeout", []string{"New York"}, "", &api.NetworkError{What: "weather", Err: context.DeadlineExceeded}, "failed to fetch weather: context deadline exceeded", 6},
+		{"Invalid response", []string{"New York"}, "", &api.ResponseError{Err: errors.New("unexpected EOF")}, "failed to parse response", 4},
+		{"Unclassified failure", []string{"New York"}, "", errors.New("boom"), "boom", 1},
+		{"Interrupted", []string{"New York"}, "", &api.NetworkError{What: "weather", Err: context.Canceled}, "interrupted", 130},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp(tt.stdin)
+			ta.weather.err = tt.weather
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.NotContains(t, ta.stdout.String(), "Temp:")
+			assert.Contains(t, ta.stderr.String(), "Error: "+tt.contains)
+		})
+	}
+}
+
+func TestRun_InterruptedPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+
+		// editor reads the lines with the line editor, as on a terminal
+		editor bool
+	}{
+		{"Session", nil, false},
+		{"Session with the line editor", nil, true},
+		{"Search", []string{"search"}, false},
+		{"Picker", []string{"fav", "add", "--pick", "home", "Paris"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Input that never arrives must not outlast an interrupt
+			ta := newTestApp("")
+			stdin, _ := io.Pipe()
+			ta.stdin = stdin
+			raw := 0
+			if tt.editor {
+				ta.rawInput = func() (func(), error) {
+					raw++
+					return func() { raw-- }, nil
+				}
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			ta.ctx = ctx
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			code := ta.run(tt.args)
+
+			// The terminal is restored before sky exits
+			assert.Equal(t, exitInterrupted, code)
+			assert.Zero(t, raw)
+			if tt.editor {
+				assert.Contains(t, ta.stdout.String(), ":help for commands")
+			}
+		})
+	}
+}
+
+func TestRun_InterruptedBeforeRequest(t *testing.T) {
+	ta := newTestApp("")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ta.ctx = ctx
+
+	code := ta.run([]string{"New York"})
+
+	// The canceled root context reaches the geocoder
+	assert.Equal(t, exitInterrupted, code)
+	assert.Equal(t, "Error: interrupted", strings.TrimSpace(ta.stderr.String()))
+}
+
+func TestRun_NotFoundHint(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"Atlantis"})
+
+	assert.Equal(t, 3, code)
+	assert.Contains(t, ta.stderr.String(), "Hint: ")
+	assert.Contains(t, ta.stderr.String(), "sky search")
+}
+
+func TestRun_HourlyMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantHours int
+	}{
+		{"Default hours", []string{"hourly", "New", "York"}, api.DefaultForecastHours},
+		{"Hours flag before city", []string{"hourly", "--hours", "3", "New", "York"}, 3},
+		{"Hours flag after city", []string{"hourly", "New", "York", "-hours=6"}, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Equal(t, tt.wantHours, ta.forecast.hours)
+			assert.Contains(t, ta.stdout.String(), "New York, US")
+			assert.Contains(t, ta.stdout.String(), fmt.Sprintf("Next %d hours:", tt.wantHours))
+		})
+	}
+}
+
+func TestRun_UsageError(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"hourly", "--hours", "many", "Tokyo"})
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, ta.stderr.String(), "invalid value")
+}
+
+func TestRun_ForecastLengthOutOfRange(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"No hours", []string{"hourly", "--hours", "0", "Tokyo"}, "--hours must be between 1 and 384"},
+		{"Too many hours", []string{"hourly", "--hours", "385", "Tokyo"}, "--hours must be between 1 and 384"},
+		{"Negative days", []string{"forecast", "--days=-1", "Tokyo"}, "--days must be between 1 and 16"},
+		{"Too many days", []string{"forecast", "--days", "17", "Tokyo"}, "--days must be between 1 and 16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			// Rejected before any request, as bad arguments
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+			assert.Empty(t, ta.geocoder.queries)
+		})
+	}
+}
+
+func TestRun_ForecastMode(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"forecast", "--days", "3", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, 3, ta.forecast.days)
+	assert.Contains(t, ta.stdout.String(), "Next 3 days:")
+	assert.Contains(t, ta.stdout.String(), "Mon 03-03")
+}
+
+func TestRun_Chart(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		columns   string
+		width     int
+		wantWidth int
+		wantAxis  string
+	}{
+		{"Hourly", []string{"hourly", "--chart", "New York"}, "", 0, ui.DefaultChartWidth, "00"},
+		{"Daily", []string{"forecast", "--chart", "New York"}, "", 0, ui.DefaultChartWidth, "Sat Sun Mon"},
+		{"Terminal width", []string{"hourly", "--chart", "--hours", "48", "New York"}, "", 30, 30, "00"},
+		{"COLUMNS", []string{"hourly", "--chart", "--hours", "48", "New York"}, "40", 120, 40, "00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.env["COLUMNS"] = tt.columns
+			ta.width = func() int { return tt.width }
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Contains(t, ta.stdout.String(), "│")
+			assert.Contains(t, ta.stdout.String(), tt.wantAxis)
+			for _, line := range strings.Split(strings.TrimSuffix(ta.stdout.String(), "\n"), "\n") {
+				assert.LessOrEqual(t, utf8.RuneCountInString(line), tt.wantWidth, line)
+			}
+		})
+	}
+}
+
+func TestRun_ChartErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"Current conditions", []string{"--chart", "New York"}, "flag provided but not defined: -chart"},
+		{"JSON", []string{"hourly", "--chart", "--format", "json", "New York"}, "--chart only works"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, ta.stderr.String(), tt.want)
+		})
+	}
+}
+
+func TestRun_Units(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		expected api.Units
+	}{
+		{"Metric by default", []string{"New York"}, "", api.MetricUnits},
+		{"Environment default", []string{"New York"}, "imperial", api.ImperialUnits},
+		{"Flag overrides environment", []string{"--units", "scientific", "New York"}, "imperial", api.ScientificUnits},
+		{
+			"Per-quantity overrides",
+			[]string{"New York", "--units=imperial", "--wind-speed-unit", "kn", "--precipitation-unit", "mm"},
+			"",
+			api.Units{Temperature: api.Fahrenheit, WindSpeed: api.Knots, Precipitation: api.Millimeters},
+		},
+		{
+			"Kelvin with metric",
+			[]string{"--temperature-unit", "K", "New York"},
+			"",
+			api.Units{Temperature: api.Kelvin, WindSpeed: api.KilometersPerHour, Precipitation: api.Millimeters},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.env["SKY_UNITS"] = tt.env
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Equal(t, tt.expected, ta.client.Units)
+		})
+	}
+}
+
+func TestRun_InvalidUnits(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--units", "furlongs", "New York"})
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, ta.stderr.String(), "unknown unit system")
+	assert.Empty(t, ta.geocoder.queries)
+}
+
+func TestRun_ServiceURLsFromEnvironment(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["SKY_GEOCODING_URL"] = "http://geo.internal"
+	ta.env["SKY_FORECAST_URL"] = "http://forecast.internal"
+
+	code := ta.run([]string{"New York"})
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "http://geo.internal", ta.client.GeocodingURL)
+	assert.Equal(t, "http://forecast.internal", ta.client.ForecastURL)
+}
+
+func TestRun_DetailView(t *testing.T) {
+	ta := newTestApp("")
+	ta.weather.weather.Humidity = 48
+
+	code := ta.run([]string{"--detail", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "Temp: 21.3°C")
+	assert.Contains(t, ta.stdout.String(), "Humidity: 48%")
+}
+
+func TestRun_SearchMode(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"search", "--count", "2", "Paris"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "1. Paris, Île-de-France, FR")
+	assert.Contains(t, ta.stdout.String(), "2. Paris, Texas, US")
+	assert.NotContains(t, ta.stdout.String(), "Tennessee")
+	assert.NotContains(t, ta.stdout.String(), "Temp:")
+}
+
+func TestRun_InteractivePicker(t *testing.T) {
+	tests := []struct {
+		name        string
+		stdin       string
+		wantCountry string
+		wantRetry   bool
+	}{
+		{"Choose second candidate", "Paris\n2\n", "US", false},
+		{"Empty answer picks best match", "Paris\n\n", "FR", false},
+		{"Invalid answer is retried", "Paris\n7\nthree\n1\n", "FR", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp(tt.stdin)
+
+			code := ta.run(nil)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Contains(t, ta.stdout.String(), "3. Paris, Tennessee, US")
+			assert.Contains(t, ta.stdout.String(), "Choose a location [1-3]: ")
+			assert.Contains(t, ta.stdout.String(), "Paris, "+tt.wantCountry+"\nPartly cloudy")
+			assert.Equal(t, tt.wantRetry, strings.Contains(ta.stdout.String(), "Please enter a number between 1 and 3"))
+		})
+	}
+}
+
+func TestRun_InteractivePicker_ClosedInput(t *testing.T) {
+	ta := newTestApp("Paris\n")
+
+	code := ta.run(nil)
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, ta.stderr.String(), "failed to read input")
+}
+
+func TestRun_ArgumentsSkipPicker(t *testing.T) {
+	ta := newTestApp("")
+	ta.geocoder.locations["Paris"] = &ta.geocoder.candidates["Paris"][0]
+
+	code := ta.run([]string{"Paris"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.NotContains(t, ta.stdout.String(), "Choose a location")
+}
+
+func TestRun_Coordinates(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantLat float64
+		wantLon float64
+	}{
+		{"Decimal pair", []string{"35.6762,139.6503"}, 35.6762, 139.6503},
+		{"Negative decimal pair", []string{"-33.8688,151.2093"}, -33.8688, 151.2093},
+		{"Split negative pair", []string{"--detail", "-33.8688", "-70.6693"}, -33.8688, -70.6693},
+		{"DMS", []string{"35°41'N", "139°41'E"}, 35.6833, 139.6833},
+		{"Plus code", []string{"8FVC9G8F+6X"}, 47.3656, 8.5249},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Empty(t, ta.geocoder.queries, "coordinates must not be geocoded")
+			assert.InDelta(t, tt.wantLat, ta.weather.lat, 1e-4)
+			assert.InDelta(t, tt.wantLon, ta.weather.lon, 1e-4)
+		})
+	}
+}
+
+func TestRun_InteractiveCoordinatesSkipPicker(t *testing.T) {
+	ta := newTestApp("48.8534, 2.3488\n")
+
+	code := ta.run(nil)
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Empty(t, ta.geocoder.queries)
+	assert.Contains(t, ta.stdout.String(), "48.8534°N 2.3488°E\n")
+}
+
+func TestRun_InvalidCoordinates(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"95.0,10.0"})
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, ta.stderr.String(), "latitude 95.0000 is out of range")
+}
+
+func TestRun_CoordinatesLabelledByReverseGeocoding(t *testing.T) {
+	ta := newTestApp("")
+	ta.reverse.place = &api.Location{Name: "Hakone", Country: "JP", Distance: 2.74}
+
+	code := ta.run([]string{"35.2412,139.0786"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "near Hakone, JP (2.7 km)\n")
+	assert.InDelta(t, 35.2412, ta.weather.lat, 1e-9)
+	assert.InDelta(t, 139.0786, ta.weather.lon, 1e-9)
+}
+
+func TestRun_ReverseGeocodingURLFromEnvironment(t *testing.T) {
+	ta := newTestApp("")
+	ta.env["SKY_REVERSE_GEOCODING_URL"] = "http://nominatim.internal"
+
+	code := ta.run([]string{"New York"})
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "http://nominatim.internal", ta.client.ReverseGeocodingURL)
+}
+
+func TestRun_AirMode(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"air", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+	assert.Contains(t, ta.stdout.String(), "New York, US\nUS AQI: 42 (Good)\n")
+	assert.NotContains(t, ta.stdout.String(), "Temp:")
+}
+
+func TestRun_AirWithWeatherGeocodesOnce(t *testing.T) {
+	ta := newTestApp("")
+
+	code := ta.run([]string{"--air", "New York"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+	assert.Equal(t, 1, ta.air.calls)
+	assert.Contains(t, ta.stdout.String(), "Temp: 21.3°C")
+	assert.Contains(t, ta.stdout.String(), "European AQI: 25 (Fair)")
+}
+
+func TestRun_AirColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		terminal  bool
+		noColor   string
+		wantColor bool
+	}{
+		{"Terminal", true, "", true},
+		{"Pipe", false, "", false},
+		{"NO_COLOR", true, "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.terminal = tt.terminal
+			ta.env["NO_COLOR"] = tt.noColor
+
+			code := ta.run([]string{"air", "New York"})
+
+			assert.Equal(t, 0, code)
+			assert.Equal(t, tt.wantColor, strings.Contains(ta.stdout.String(), "\x1b["))
+		})
+	}
+}
+
+func TestRun_Color(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		terminal bool
+		env      map[string]string
+		want     string
+	}{
+		{"Always on a pipe", []string{"--color", "always", "Tokyo"}, false, nil, "\x1b[3"},
+		{"Never on a terminal", []string{"--color=never", "Tokyo"}, true, nil, ""},
+		{"Truecolor terminal", []string{"Tokyo"}, true, map[string]string{"COLORTERM": "truecolor"}, "\x1b[38;2;"},
+		{"256-color terminal", []string{"Tokyo"}, true, map[string]string{"TERM": "xterm-256color"}, "\x1b[38;5;"},
+		{"Dumb terminal", []string{"Tokyo"}, true, map[string]string{"TERM": "dumb"}, ""},
+		{"FORCE_COLOR on a pipe", []string{"Tokyo"}, false, map[string]string{"FORCE_COLOR": "3"}, "\x1b[38;2;"},
+		{"FORCE_COLOR=0", []string{"Tokyo"}, true, map[string]string{"FORCE_COLOR": "0"}, ""},
+		{"JSON is plain", []string{"--color", "always", "--format", "json", "Tokyo"}, true, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.geocoder.locations["Tokyo"] = &api.Location{Name: "Tokyo", Country: "JP"}
+			ta.terminal = tt.terminal
+			for k, v := range tt.env {
+				ta.env[k] = v
+			}
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code)
+			if tt.want == "" {
+				assert.NotContains(t, ta.stdout.String(), "\x1b[")
+			} else {
+				assert.Contains(t, ta.stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_ColorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{"Color setting", []string{"--color", "sometimes", "Tokyo"}, nil, `unknown color setting "sometimes"`},
+		{"Palette flag", []string{"--palette", "sepia", "Tokyo"}, nil, `unknown palette "sepia"`},
+		{"Palette env", []string{"Tokyo"}, map[string]string{"SKY_PALETTE": "sepia"}, `unknown palette "sepia"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			for k, v := range tt.env {
+				ta.env[k] = v
+			}
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, ta.stderr.String(), tt.want)
+		})
+	}
+}
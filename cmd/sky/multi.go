@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// multiWorkers bounds the concurrent requests of a multi-location query
+const multiWorkers = 4
+
+// splitLocations splits input into the locations separated by ";"
+func splitLocations(input string) []string {
+	var locations []string
+	for _, part := range strings.Split(input, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			locations = append(locations, part)
+		}
+	}
+	return locations
+}
+
+// showMulti prints the current conditions for each input, in input order.
+// A failure for one location is reported on stderr without stopping the
+// others; the exit code is that of the first failure.
+func (a *app) showMulti(ctx context.Context, inputs []string, detail bool) int {
+	locations := make([]*api.Location, len(inputs))
+	errs := parallel(ctx, len(inputs), func(ctx context.Context, i int) (err error) {
+		locations[i], err = a.resolveLocation(ctx, inputs[i])
+		return err
+	})
+
+	var found []int
+	for i := range inputs {
+		if errs[i] == nil {
+			found = append(found, i)
+			a.remember(locations[i])
+		}
+	}
+
+	weathers := make([]*api.Weather, len(inputs))
+	if batch, ok := a.weather.(api.BatchWeatherProvider); ok && len(found) > 1 {
+		points := make([]api.Coordinates, len(found))
+		for j, i := range found {
+			points[j] = api.Coordinates{Latitude: locations[i].Latitude, Longitude: locations[i].Longitude}
+		}
+		// One bad location fails the whole batch; the fallback below isolates it
+		if results, err := batch.GetWeatherBatch(ctx, points); err == nil {
+			for j, i := range found {
+				weathers[i] = results[j]
+			}
+		}
+	}
+
+	fetchErrs := parallel(ctx, len(found), func(ctx context.Context, j int) (err error) {
+		i := found[j]
+		if weathers[i] == nil {
+			weathers[i], err = a.weather.GetWeather(ctx, locations[i].Latitude, locations[i].Longitude)
+		}
+		return err
+	})
+	for j, i := range found {
+		errs[i] = fetchErrs[j]
+	}
+
+	code := exitOK
+	printed := false
+	for i, input := range inputs {
+		var out string
+		err := errs[i]
+		if err == nil {
+			out, err = a.formatWeather(locations[i], weathers[i], detail)
+		}
+		if err != nil {
+			a.printError(fmt.Errorf("%s: %w", input, err), exitCode(err))
+			if code == exitOK {
+				code = exitCode(err)
+			}
+			continue
+		}
+
+		// JSON and template results are one per line
+		if printed && !a.json && a.template == nil {
+			fmt.Fprintln(a.stdout)
+		}
+		printed = true
+		fmt.Fprint(a.stdout, out)
+	}
+	return code
+}
+
+// resolveLocation looks up a known place or geocodes input, labelling
+// coordinates with the nearest place
+func (a *app) resolveLocation(ctx context.Context, input string) (*api.Location, error) {
+	if location, err := a.knownLocation(input); location != nil || err != nil {
+		return location, err
+	}
+	location, err := coordinateLocation(input)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return a.geocoder.GetLocation(ctx, input)
+	}
+	if place, err := a.reverse.ReverseGeocode(ctx, location.Latitude, location.Longitude); err == nil {
+		return place, nil
+	}
+	return location, nil
+}
+
+// parallel calls fn for each index below n on at most multiWorkers goroutines
+// and returns the error of each call. Indexes not started before ctx is
+// done report ctx's error.
+func parallel(ctx context.Context, n int, fn func(ctx context.Context, i int) error) []error {
+	errs := make([]error, n)
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(multiWorkers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(ctx, i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// newMultiTestApp returns a test app that also knows Tokyo and London
+func newMultiTestApp() *testApp {
+	ta := newTestApp("")
+	ta.geocoder.locations["Tokyo"] = &api.Location{Name: "Tokyo", Country: "JP", Latitude: 35.6895, Longitude: 139.6917}
+	ta.geocoder.locations["London"] = &api.Location{Name: "London", Country: "GB", Latitude: 51.5085, Longitude: -0.1257}
+	return ta
+}
+
+// headers returns the location header lines of the printed weather blocks
+func headers(output string) []string {
+	var result []string
+	for _, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		result = append(result, strings.SplitN(block, "\n", 2)[0])
+	}
+	return result
+}
+
+func TestSplitLocations(t *testing.T) {
+	assert.Equal(t, []string{"Tokyo", "New York"}, splitLocations(" Tokyo ;New York;; "))
+	assert.Equal(t, []string{"New York"}, splitLocations("New York"))
+	assert.Empty(t, splitLocations(" ; "))
+}
+
+func TestRun_MultiLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Flag", []string{"-m", "Tokyo", "London", "New York"}},
+		{"Long flag", []string{"--multi", "Tokyo", "London", "New York"}},
+		{"Separator", []string{"Tokyo;", "London;", "New", "York"}},
+		{"Quoted separator", []string{"Tokyo; London; New York"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newMultiTestApp()
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, 0, code, ta.stderr.String())
+			assert.Equal(t, []string{"Tokyo, JP", "London, GB", "New York, US"}, headers(ta.stdout.String()))
+			assert.Equal(t, 1, ta.weather.batches)
+			assert.Equal(t, 0, ta.weather.calls)
+		})
+	}
+}
+
+func TestRun_MultiLocationInteractive(t *testing.T) {
+	ta := newMultiTestApp()
+	ta.stdin = strings.NewReader("Tokyo; London\n")
+
+	code := ta.run(nil)
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "Tokyo, JP")
+	assert.Contains(t, ta.stdout.String(), "London, GB")
+}
+
+func TestRun_MultiLocationFailureIsolation(t *testing.T) {
+	ta := newMultiTestApp()
+
+	code := ta.run([]string{"-m", "Tokyo", "Atlantis", "London"})
+
+	assert.Equal(t, 3, code)
+	assert.Equal(t, []string{"Tokyo, JP", "London, GB"}, headers(ta.stdout.String()))
+	assert.Contains(t, ta.stderr.String(), "Error: Atlantis: location not found")
+}
+
+func TestRun_MultiLocationWeatherFailure(t *testing.T) {
+	ta := newMultiTestApp()
+	ta.weather.failAt = map[float64]error{51.5085: &api.APIError{StatusCode: 400, Reason: "bad location"}}
+
+	code := ta.run([]string{"-m", "Tokyo", "London", "New York"})
+
+	// The failed batch is retried location by location
+	assert.Equal(t, 4, code)
+	assert.Equal(t, 1, ta.weather.batches)
+	assert.Equal(t, 3, ta.weather.calls)
+	assert.Equal(t, []string{"Tokyo, JP", "New York, US"}, headers(ta.stdout.String()))
+	assert.Contains(t, ta.stderr.String(), "Error: London: API returned status 400: bad location")
+}
+
+func TestRun_MultiLocationWithoutBatch(t *testing.T) {
+	ta := newMultiTestApp()
+	connect := ta.connect
+	ta.connect = func(client *api.Client) services {
+		s := connect(client)
+		// Hide GetWeatherBatch, as a provider without batching does
+		s.weather = struct{ api.WeatherProvider }{s.weather}
+		return s
+	}
+
+	code := ta.run([]string{"-m", "Tokyo", "London", "New York", "35.6762,139.6503"})
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, 0, ta.weather.batches)
+	assert.Equal(t, 4, ta.weather.calls)
+	assert.Equal(t, []string{"Tokyo, JP", "London, GB", "New York, US", "35.6762°N 139.6503°E"}, headers(ta.stdout.String()))
+}
+
+func TestRun_MultiLocationCached(t *testing.T) {
+	ta := newMultiTestApp()
+	ta.env["XDG_CACHE_HOME"] = t.TempDir()
+	args := []string{"--cache", "-m", "Tokyo", "London", "New York"}
+
+	code := ta.run(args)
+
+	// The cache passes the locations on as one batch
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, []string{"Tokyo, JP", "London, GB", "New York, US"}, headers(ta.stdout.String()))
+	assert.Equal(t, 1, ta.weather.batches)
+	assert.Equal(t, 0, ta.weather.calls)
+
+	// Fresh conditions are then served without any request
+	ta.stdout.Reset()
+	code = ta.run(args)
+
+	assert.Equal(t, 0, code, ta.stderr.String())
+	assert.Equal(t, []string{"Tokyo, JP", "London, GB", "New York, US"}, headers(ta.stdout.String()))
+	assert.Equal(t, 1, ta.weather.batches)
+	assert.Equal(t, 0, ta.weather.calls)
+}
+
+func TestRun_MultiLocationUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"hourly", "Tokyo; London"},
+		{"forecast", "Tokyo;London"},
+		{"--air", "-m", "Tokyo", "London"},
+	} {
+		ta := newMultiTestApp()
+
+		assert.Equal(t, 2, ta.run(args), args)
+		assert.Contains(t, ta.stderr.String(), "multiple locations")
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+
+	errs := parallel(t.Context(), 10, func(ctx context.Context, i int) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if i%3 == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	assert.LessOrEqual(t, peak, multiWorkers)
+	for i, err := range errs {
+		assert.Equal(t, i%3 == 0, err != nil, "index %d", i)
+	}
+}
+
+func TestParallel_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	errs := parallel(ctx, 3, func(ctx context.Context, i int) error { return nil })
+
+	for _, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+import "syscall"
+
+// The ioctl requests reading and writing terminal attributes
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
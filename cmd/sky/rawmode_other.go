@@ -0,0 +1,14 @@
+//go:build !(linux || darwin)
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw fails: raw mode is only supported on Linux and macOS, elsewhere the
+// interactive session reads whole lines without editing keys
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+	"github.com/kakkoiirus/sky-cli/internal/lineedit"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
+)
+
+// historyFile is the name of the session history in the state directory
+const historyFile = "history"
+
+// recentPlaces bounds the places an interactive session remembers for completion
+const recentPlaces = 50
+
+// replCommand is a command of the interactive session
+type replCommand struct {
+	name, args, summary string
+}
+
+// replCommands are the commands of the interactive session, in the order shown by :help
+var replCommands = []replCommand{
+	{":now", "", "current conditions"},
+	{":hourly", "[hours]", "hour-by-hour forecast"},
+	{":forecast", "[days]", "day-by-day forecast"},
+	{":air", "", "air quality and pollen"},
+	{":units", "metric|imperial|scientific", "change the unit system"},
+	{":help", "", "show this help"},
+	{":quit", "", "end the session (also Ctrl+D)"},
+}
+
+// unitSystems are the unit systems offered by :units
+var unitSystems = []string{"metric", "imperial", "scientific"}
+
+// runREPL runs an interactive session showing the weather for each place
+// entered, starting in mode, until the input ends or :quit. On a terminal,
+// lines can be edited, recalled from the history and tab-completed.
+// It returns the exit code of the last command.
+func (a *app) runREPL(mode string, o *options) int {
+	if a.canEdit() {
+		out := a.stdout
+		if a.json {
+			out = a.stderr
+		}
+		a.editor = lineedit.New(a.stdin, out)
+		a.editor.Raw = a.rawInput
+		a.editor.Complete = a.completeREPL
+		defer func() { a.editor = nil }()
+
+		// The history is kept for terminals only; piped input is not worth recalling
+		historyPath, history, err := a.loadHistory()
+		if err != nil {
+			a.printError(err, exitFailure)
+			history = lineedit.NewHistory(lineedit.DefaultHistorySize)
+		}
+		a.editor.History = history
+		if historyPath != "" {
+			defer a.saveHistory(historyPath, history)
+		}
+		fmt.Fprintln(out, "Enter a place, or :help for commands. :quit or Ctrl+D ends the session.")
+	}
+
+	code := exitOK
+	for {
+		// Ctrl+C clears the line being edited; during a request, or at a
+		// prompt read without the line editor, it ends the session
+		if a.ctx.Err() != nil {
+			return exitInterrupted
+		}
+		line, err := a.readLine(replPrompt(mode))
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, errInterrupted) {
+			return exitInterrupted
+		}
+		if err != nil {
+			return code
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if a.editor != nil {
+			a.editor.History.Add(line)
+		}
+
+		if !strings.HasPrefix(line, ":") {
+			code = a.show(mode, o, splitLocations(line), true)
+			continue
+		}
+		quit, result := a.replCommand(&mode, o, line)
+		if quit {
+			return code
+		}
+		code = result
+	}
+}
+
+// replPrompt returns the prompt of an interactive session in mode
+func replPrompt(mode string) string {
+	if mode == defaultCommand {
+		return "sky> "
+	}
+	return "sky " + mode + "> "
+}
+
+// replCommand runs a session command, switching *mode or the units in o.
+// quit is set when the session should end.
+func (a *app) replCommand(mode *string, o *options, line string) (quit bool, code int) {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case ":quit", ":q", ":exit":
+		return true, exitOK
+	case ":help":
+		a.replHelp()
+		return false, exitOK
+	case ":now", ":air":
+		if len(args) > 0 {
+			return false, a.usageError(fmt.Errorf("%s takes no arguments", name))
+		}
+		return false, a.switchMode(mode, strings.TrimPrefix(name, ":"), o)
+	case ":hourly", ":forecast":
+		count, limit := &o.hours, api.MaxForecastHours
+		if name == ":forecast" {
+			count, limit = &o.days, api.MaxForecastDays
+		}
+		if len(args) > 1 {
+			return false, a.usageError(fmt.Errorf("usage: %s [count]", name))
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > limit {
+				return false, a.usageError(fmt.Errorf("invalid count %q for %s (want 1 to %d)", args[0], name, limit))
+			}
+			*count = n
+		} else if *count == 0 {
+			// Sessions started by other commands have no count flag
+			*count = api.DefaultForecastHours
+			if name == ":forecast" {
+				*count = api.DefaultForecastDays
+			}
+		}
+		return false, a.switchMode(mode, strings.TrimPrefix(name, ":"), o)
+	case ":units":
+		if len(args) != 1 {
+			return false, a.usageError(fmt.Errorf("usage: :units %s", strings.Join(unitSystems, "|")))
+		}
+		previous := o.units
+		o.units = unitFlags{system: args[0]}
+		if err := a.connectServices(*mode, o); err != nil {
+			o.units = previous
+			return false, a.usageError(err)
+		}
+		return false, exitOK
+	default:
+		return false, a.usageError(fmt.Errorf("unknown command %q (see :help)", name))
+	}
+}
+
+// switchMode makes the session show next, if the options allow it
+func (a *app) switchMode(mode *string, next string, o *options) int {
+	if err := a.connectServices(next, o); err != nil {
+		return a.usageError(err)
+	}
+	*mode = next
+	return exitOK
+}
+
+// replHelp prints the session commands
+func (a *app) replHelp() {
+	fmt.Fprint(a.stdout, "Enter a place to show its weather, or several separated by \";\".\n"+
+		"Favorites are entered as @alias. Commands:\n")
+	for _, c := range replCommands {
+		fmt.Fprintf(a.stdout, "  %-36s  %s\n", strings.TrimSpace(c.name+" "+c.args), c.summary)
+	}
+}
+
+// completeREPL returns the completions of the text before the cursor:
+// session commands, or favorites and the places found during the session
+func (a *app) completeREPL(before string) []string {
+	var completions []string
+	if strings.HasPrefix(before, ":") {
+		name, arg, hasArg := strings.Cut(before, " ")
+		switch {
+		case !hasArg:
+			for _, c := range replCommands {
+				if strings.HasPrefix(c.name, name) {
+					completions = append(completions, c.name)
+				}
+			}
+		case name == ":units":
+			for _, system := range unitSystems {
+				if hasPrefixFold(system, strings.TrimSpace(arg)) {
+					completions = append(completions, ":units "+system)
+				}
+			}
+		}
+		return completions
+	}
+
+	// Complete the last of several places
+	head, word := "", strings.TrimLeft(before, " ")
+	if i := strings.LastIndex(before, ";"); i >= 0 {
+		head, word = before[:i+1]+" ", strings.TrimLeft(before[i+1:], " ")
+	}
+
+	var places []string
+	if _, list, err := a.readFavorites(); err == nil {
+		for _, f := range list.All() {
+			places = append(places, favorites.Prefix+f.Alias)
+		}
+	}
+	for _, location := range a.recent {
+		places = append(places, ui.LocationLabel(&location))
+	}
+	for _, place := range places {
+		if hasPrefixFold(place, word) {
+			completions = append(completions, head+place)
+		}
+	}
+	return completions
+}
+
+// hasPrefixFold reports whether s begins with prefix, ignoring case
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// canEdit reports whether lines can be edited: stdin and stdout are a
+// terminal that can be switched to raw mode
+func (a *app) canEdit() bool {
+	if a.rawInput == nil {
+		return false
+	}
+	restore, err := a.rawInput()
+	if err != nil {
+		return false
+	}
+	restore()
+	return true
+}
+
+// stateDir returns the directory of the sky state: $XDG_STATE_HOME/sky, or
+// ~/.local/state/sky when XDG_STATE_HOME is unset
+func (a *app) stateDir() (string, error) {
+	if dir := a.getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "sky"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find state directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "sky"), nil
+}
+
+// loadHistory returns the path of the session history and its lines
+func (a *app) loadHistory() (string, *lineedit.History, error) {
+	dir, err := a.stateDir()
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, historyFile)
+	history, err := lineedit.LoadHistory(path, lineedit.DefaultHistorySize)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, history, nil
+}
+
+// saveHistory writes the session history to path when the session ends
+func (a *app) saveHistory(path string, history *lineedit.History) {
+	if err := history.Save(path); err != nil {
+		a.printError(err, exitFailure)
+	}
+}
+
+// knownLocation returns the location of a favorite, or of a place found
+// earlier in the session and entered by its full label, or nil
+func (a *app) knownLocation(input string) (*api.Location, error) {
+	if location, err := a.favoriteLocation(input); location != nil || err != nil {
+		return location, err
+	}
+	for _, location := range a.recent {
+		if strings.EqualFold(ui.LocationLabel(&location), strings.TrimSpace(input)) {
+			return &location, nil
+		}
+	}
+	return nil, nil
+}
+
+// remember records a place found during the session for completion
+func (a *app) remember(location *api.Location) {
+	label := ui.LocationLabel(location)
+	recent := []api.Location{*location}
+	for _, l := range a.recent {
+		if ui.LocationLabel(&l) != label && len(recent) < recentPlaces {
+			recent = append(recent, l)
+		}
+	}
+	a.recent = recent
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+func TestRun_REPL(t *testing.T) {
+	ta := newMultiTestApp()
+	ta.stdin = strings.NewReader("New York\n\n:units imperial\nTokyo; London\n:forecast\nLondon\n:hourly 2\nTokyo\n:quit\nNew York\n")
+
+	code := ta.run(nil)
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	out := ta.stdout.String()
+	assert.Contains(t, out, "sky> New York, US\nPartly cloudy")
+	assert.Contains(t, out, "sky> Tokyo, JP\n")
+	assert.Contains(t, out, "sky> sky forecast> London, GB\nNext 7 days:")
+	assert.Contains(t, out, "sky forecast> sky hourly> Tokyo, JP\nNext 2 hours:")
+	assert.Equal(t, api.ImperialUnits, ta.client.Units)
+	assert.Equal(t, 7, ta.forecast.days)
+	assert.Equal(t, 2, ta.forecast.hours)
+
+	// Input after :quit is not read
+	assert.NotContains(t, out[len(out)-20:], "New York")
+}
+
+func TestRun_REPLStartsInMode(t *testing.T) {
+	ta := newTestApp("New York\n")
+
+	code := ta.run([]string{"forecast", "--days", "3"})
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Contains(t, ta.stdout.String(), "sky forecast> New York, US\nNext 3 days:")
+}
+
+func TestRun_REPLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+		err   string
+	}{
+		{"Unknown place keeps the session", nil, "Atlantis\nNew York\n", exitOK, "Error: location not found"},
+		{"Ends with the last failure", nil, "New York\nAtlantis\n", exitNotFound, "Error: location not found"},
+		{"Unknown command", nil, ":weather\n", exitUsage, `unknown command ":weather" (see :help)`},
+		{"Unknown units", nil, ":units nautical\n", exitUsage, `unknown unit system "nautical"`},
+		{"Missing units", nil, ":units\n", exitUsage, "usage: :units metric|imperial|scientific"},
+		{"Invalid count", nil, ":hourly many\n", exitUsage, `invalid count "many" for :hourly`},
+		{"Count out of range", nil, ":forecast 17\n", exitUsage, `invalid count "17" for :forecast (want 1 to 16)`},
+		{"Arguments to :air", nil, ":air now\n", exitUsage, ":air takes no arguments"},
+		{"Offline forecast", []string{"--offline"}, ":forecast\n", exitUsage, "--offline only works for current conditions"},
+		{"Several places in forecasts", nil, ":forecast\nTokyo; New York\n", exitUsage, "multiple locations only work for current conditions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp(tt.input)
+			ta.env["XDG_CACHE_HOME"] = t.TempDir()
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+		})
+	}
+}
+
+func TestRun_REPLHelp(t *testing.T) {
+	ta := newTestApp(":help\n")
+
+	code := ta.run(nil)
+
+	assert.Equal(t, exitOK, code)
+	for _, c := range replCommands {
+		assert.Contains(t, ta.stdout.String(), "  "+c.name)
+	}
+}
+
+func TestRun_REPLRemembersPlaces(t *testing.T) {
+	ta := newTestApp("Paris\n2\nparis, texas, us\n")
+
+	code := ta.run(nil)
+
+	// The place chosen first is reused by its label without searching again
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Equal(t, []string{"Paris"}, ta.geocoder.queries)
+	assert.Equal(t, "Paris", ta.recent[0].Name)
+	assert.Equal(t, "Texas", ta.recent[0].Admin1)
+}
+
+func TestRun_REPLTerminal(t *testing.T) {
+	ta := newTestApp("")
+	withConfig(t, ta, "")
+	state := t.TempDir()
+	ta.env["XDG_STATE_HOME"] = state
+	raw := 0
+	ta.rawInput = func() (func(), error) {
+		raw++
+		return func() {}, nil
+	}
+	// Type New York, recall it with the up arrow, then end with Ctrl+D
+	ta.stdin = strings.NewReader("New Yrk\x1b[D\x1b[Do\r\x1b[A\r\x04")
+
+	code := ta.run(nil)
+
+	// Raw mode is checked once, then entered for each line
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Equal(t, 4, raw)
+	assert.Equal(t, []string{"New York", "New York"}, ta.geocoder.queries)
+	assert.Contains(t, ta.stdout.String(), ":help for commands")
+
+	// The history is state, saved once the session ends
+	data, err := os.ReadFile(filepath.Join(state, "sky", "history"))
+	require.NoError(t, err)
+	assert.Equal(t, "New York\n", string(data))
+}
+
+func TestRun_REPLWithoutRawMode(t *testing.T) {
+	ta := newTestApp("New York\n")
+	ta.rawInput = func() (func(), error) {
+		return nil, errors.New("raw terminal mode is not supported on this platform")
+	}
+
+	code := ta.run(nil)
+
+	// Whole lines are read without the line editor
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Empty(t, ta.stderr.String())
+	assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+	assert.NotContains(t, ta.stdout.String(), ":help for commands")
+}
+
+func TestCompleteREPL(t *testing.T) {
+	ta := newTestApp("")
+	withConfig(t, ta, "")
+	require.Equal(t, exitOK, ta.run([]string{"fav", "add", "home", "New York"}))
+	ta.remember(&api.Location{Name: "Paris", Admin1: "Texas", Country: "US"})
+	ta.remember(&api.Location{Name: "Paris", Admin1: "Île-de-France", Country: "FR"})
+
+	tests := []struct {
+		before string
+		want   []string
+	}{
+		{":h", []string{":hourly", ":help"}},
+		{":units im", []string{":units imperial"}},
+		{":hourly 1", nil},
+		{"@", []string{"@home"}},
+		{"par", []string{"Paris, Île-de-France, FR", "Paris, Texas, US"}},
+		{"Tokyo;  @h", []string{"Tokyo; @home"}},
+		{"Lima", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.before, func(t *testing.T) {
+			assert.Equal(t, tt.want, ta.completeREPL(tt.before))
+		})
+	}
+}
+
+func TestRemember(t *testing.T) {
+	a := &app{}
+	for i := 0; i < recentPlaces+5; i++ {
+		a.remember(&api.Location{Name: "Place " + strconv.Itoa(i)})
+	}
+	a.remember(&api.Location{Name: "Place 10"})
+
+	// The newest place comes first and the oldest ones are dropped
+	assert.Len(t, a.recent, recentPlaces)
+	assert.Equal(t, "Place 10", a.recent[0].Name)
+	assert.Equal(t, "Place 54", a.recent[1].Name)
+	assert.Equal(t, "Place 5", a.recent[recentPlaces-1].Name)
+}
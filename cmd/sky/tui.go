@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
+)
+
+// Keys understood by the dashboard
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyRefresh   = "refresh"
+	keyQuit      = "quit"
+	keyInterrupt = "interrupt"
+)
+
+// tuiPlace is a place listed in the dashboard with its last good readings
+type tuiPlace struct {
+	// input is the place as given, or a favorite as @alias
+	input string
+
+	location *api.Location
+	weather  *api.Weather
+	hourly   *api.HourlyForecast
+	daily    *api.DailyForecast
+	updated  time.Time
+
+	// err is the failure of the latest update, nil once an update succeeds
+	err    error
+	failed time.Time
+
+	// next is when the next update is due; loading is set while one runs
+	next    time.Time
+	loading bool
+}
+
+// tuiUpdate is the outcome of fetching the readings of places[index]
+type tuiUpdate struct {
+	index    int
+	location *api.Location
+	weather  *api.Weather
+	hourly   *api.HourlyForecast
+	daily    *api.DailyForecast
+	err      error
+}
+
+// runTUI shows a full-screen dashboard of the places in inputs followed by
+// the favorites. When stdout is not a terminal, or one without raw mode,
+// it prints the current conditions at the first place instead.
+func (a *app) runTUI(o *options, inputs []string) int {
+	if o.interval < minWatchInterval {
+		return a.usageError(fmt.Errorf("--interval must be at least %s", ui.FormatDuration(minWatchInterval)))
+	}
+	places, err := a.tuiPlaces(inputs)
+	if err != nil {
+		return a.fail(err)
+	}
+	if len(places) == 0 {
+		return a.usageError(fmt.Errorf("sky tui needs a location or a favorite (see sky fav add)"))
+	}
+
+	if a.terminal && !a.json && a.rawInput != nil && a.width() > 0 && a.height() > 0 {
+		// Keys are read one at a time where the terminal supports raw mode
+		if restore, err := a.rawInput(); err == nil {
+			fmt.Fprint(a.stdout, enterScreen)
+			code := a.dashboard(places, o)
+			fmt.Fprint(a.stdout, leaveScreen)
+			restore()
+			return code
+		}
+	}
+	return a.show("now", o, []string{places[0].input}, false)
+}
+
+// tuiPlaces returns the places in inputs followed by the favorites not among them
+func (a *app) tuiPlaces(inputs []string) ([]*tuiPlace, error) {
+	var places []*tuiPlace
+	listed := make(map[string]bool)
+	for _, input := range inputs {
+		places = append(places, &tuiPlace{input: input})
+		listed[strings.ToLower(input)] = true
+	}
+
+	_, list, err := a.readFavorites()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range list.All() {
+		input := favorites.Prefix + f.Alias
+		if !listed[strings.ToLower(input)] {
+			places = append(places, &tuiPlace{input: input})
+		}
+	}
+	return places, nil
+}
+
+// dashboard runs the dashboard until q, Ctrl+C or the end of the input.
+// The selected place is updated every --interval, and failed updates keep
+// the last good readings under a banner.
+func (a *app) dashboard(places []*tuiPlace, o *options) int {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+
+	keys := make(chan string)
+	go readKeys(a.stdin, keys)
+
+	resized := make(chan os.Signal, 1)
+	if a.resized != nil {
+		a.resized(resized)
+		defer signal.Stop(resized)
+	}
+
+	// Each place has at most one update running, so sends never block
+	updates := make(chan tuiUpdate, len(places))
+
+	selected := 0
+	for {
+		p := places[selected]
+		if !p.loading && !a.now().Before(p.next) {
+			p.loading = true
+			go func(index int, p tuiPlace) {
+				updates <- a.fetchPlace(ctx, index, &p)
+			}(selected, *p)
+		}
+		a.drawDashboard(places, selected, o.interval)
+
+		select {
+		case <-ctx.Done():
+			return exitInterrupted
+		case key, ok := <-keys:
+			switch {
+			case !ok, key == keyQuit:
+				return exitOK
+			case key == keyInterrupt:
+				return exitInterrupted
+			case key == keyUp:
+				selected = (selected + len(places) - 1) % len(places)
+			case key == keyDown:
+				selected = (selected + 1) % len(places)
+			case key == keyRefresh:
+				p.next = time.Time{}
+			}
+		case u := <-updates:
+			a.applyUpdate(places[u.index], u, o.interval)
+		case <-resized:
+		case <-a.after(watchTick):
+		}
+	}
+}
+
+// fetchPlace looks up p if needed and fetches its current conditions and forecasts
+func (a *app) fetchPlace(ctx context.Context, index int, p *tuiPlace) tuiUpdate {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	u := tuiUpdate{index: index, location: p.location}
+	if u.location == nil {
+		if u.location, u.err = a.resolveLocation(ctx, p.input); u.err != nil {
+			return u
+		}
+	}
+
+	lat, lon := u.location.Latitude, u.location.Longitude
+	errs := parallel(ctx, 3, func(ctx context.Context, i int) (err error) {
+		switch i {
+		case 0:
+			u.weather, err = a.weather.GetWeather(ctx, lat, lon)
+		case 1:
+			u.hourly, err = a.forecast.GetHourlyForecast(ctx, lat, lon, api.DefaultForecastHours)
+		default:
+			u.daily, err = a.forecast.GetDailyForecast(ctx, lat, lon, api.DefaultForecastDays)
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			u.err = err
+			break
+		}
+	}
+	return u
+}
+
+// applyUpdate stores the outcome of an update of p and schedules the next
+// one: after the interval, or sooner after a transient failure
+func (a *app) applyUpdate(p *tuiPlace, u tuiUpdate, interval time.Duration) {
+	now := a.now()
+	p.loading = false
+	if u.location != nil {
+		p.location = u.location
+	}
+	if u.err != nil {
+		p.err, p.failed = u.err, now
+		p.next = now.Add(interval)
+		if transientFailure(u.err) {
+			p.next = now.Add(min(interval, watchRetry))
+		}
+		return
+	}
+	p.weather, p.hourly, p.daily = u.weather, u.hourly, u.daily
+	p.updated, p.err = now, nil
+	p.next = now.Add(interval)
+}
+
+// drawDashboard redraws the screen for the selected place
+func (a *app) drawDashboard(places []*tuiPlace, selected int, interval time.Duration) {
+	p := places[selected]
+	d := &ui.Dashboard{
+		Selected: selected,
+		Location: p.location,
+		Weather:  p.weather,
+		Hourly:   p.hourly,
+		Daily:    p.daily,
+		Updated:  p.updated,
+		Err:      p.err,
+		Failed:   p.failed,
+		Retry:    p.next.Sub(p.failed),
+		Interval: interval,
+	}
+	for _, place := range places {
+		d.Places = append(d.Places, place.input)
+	}
+
+	lines := ui.FormatDashboard(d, a.now(), a.width(), a.height(), a.style)
+	fmt.Fprint(a.stdout, "\x1b[H"+strings.Join(lines, "\n"))
+}
+
+// readKeys sends the dashboard keys typed on in, closing keys when the input ends
+func readKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+	r := bufio.NewReader(in)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+
+		key := ""
+		switch b {
+		case 'k', keyCtrlP:
+			key = keyUp
+		case 'j', keyCtrlN:
+			key = keyDown
+		case 'r', 'R':
+			key = keyRefresh
+		case 'q', 'Q':
+			key = keyQuit
+		case keyCtrlC:
+			key = keyInterrupt
+		case keyEscape:
+			key = readEscape(r)
+		}
+		if key != "" {
+			keys <- key
+		}
+	}
+}
+
+// readEscape returns the key of the arrow key sequence following an escape, if any
+func readEscape(r *bufio.Reader) string {
+	if next, err := r.ReadByte(); err != nil || next != '[' && next != 'O' {
+		return ""
+	}
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return ""
+		}
+		if b < 0x40 || b > 0x7e {
+			continue
+		}
+		switch b {
+		case 'A':
+			return keyUp
+		case 'B':
+			return keyDown
+		}
+		return ""
+	}
+}
+
+// Control characters read by the dashboard
+const (
+	keyCtrlC  = 3
+	keyCtrlN  = 14
+	keyCtrlP  = 16
+	keyEscape = 27
+)
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// fakeScreen is a terminal showing the dashboard that calls onFrame with each frame drawn
+type fakeScreen struct {
+	bytes.Buffer
+	onFrame func(frame string)
+}
+
+func (s *fakeScreen) Write(p []byte) (int, error) {
+	if s.onFrame != nil && bytes.HasPrefix(p, []byte("\x1b[H")) {
+		s.onFrame(string(p))
+	}
+	return s.Buffer.Write(p)
+}
+
+// newTUITest returns a test app on a 100x40 terminal with @home as a favorite.
+// Keys written to the returned pipe are typed on the dashboard.
+func newTUITest(t *testing.T) (*testApp, *fakeScreen, *io.PipeWriter) {
+	ta := newMultiTestApp()
+	withConfig(t, ta, "")
+	require.Equal(t, exitOK, ta.run([]string{"fav", "add", "home", "New York"}))
+	ta.geocoder.queries = nil
+
+	screen := &fakeScreen{}
+	keys, typed := io.Pipe()
+	t.Cleanup(func() { typed.Close() })
+
+	ta.stdin = keys
+	ta.stdout = &screen.Buffer
+	ta.app.stdout = screen
+	ta.terminal = true
+	ta.width = func() int { return 100 }
+	ta.height = func() int { return 40 }
+	ta.rawInput = func() (func(), error) { return func() {}, nil }
+	ta.now = func() time.Time { return time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC) }
+	// Only keys and updates redraw the screen
+	ta.after = func(time.Duration) <-chan time.Time { return nil }
+	ta.timeout = requestTimeout
+	return ta, screen, typed
+}
+
+func TestRun_TUI(t *testing.T) {
+	ta, screen, typed := newTUITest(t)
+	var frames []string
+	screen.onFrame = func(frame string) {
+		frames = append(frames, frame)
+		switch {
+		case strings.Contains(frame, "▸ @home") && strings.Contains(frame, "Next 7 days:"):
+			go typed.Write([]byte("q"))
+		case strings.Contains(frame, "Next 7 days:") && strings.Contains(frame, "▸ Tokyo"):
+			go typed.Write([]byte("\x1b[B"))
+		}
+	}
+
+	code := ta.run([]string{"tui", "Tokyo"})
+
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Empty(t, ta.stderr.String())
+	out := screen.String()
+	assert.True(t, strings.HasPrefix(out, enterScreen))
+	assert.True(t, strings.HasSuffix(out, leaveScreen))
+
+	// The first frame is drawn while the place is looked up
+	assert.Contains(t, frames[0], "sky · Tokyo")
+	assert.Contains(t, frames[0], "Loading…")
+	assert.Contains(t, frames[0], "↑/↓ select · r refresh · q quit")
+	assert.Len(t, strings.Split(frames[0], "\n"), 40)
+
+	// The selected place shows its conditions, hourly chart and daily table
+	last := frames[len(frames)-1]
+	assert.Contains(t, last, "sky · @home")
+	assert.Contains(t, last, "Updated just now · every 10m")
+	assert.Contains(t, last, "Next 7 days:")
+	assert.Contains(t, last, "│")
+	assert.Equal(t, []string{"Tokyo"}, ta.geocoder.queries)
+	assert.Equal(t, api.DefaultForecastHours, ta.forecast.hours)
+	assert.Equal(t, api.DefaultForecastDays, ta.forecast.days)
+}
+
+func TestRun_TUIInterrupted(t *testing.T) {
+	ta, screen, _ := newTUITest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ta.ctx = ctx
+	screen.onFrame = func(frame string) {
+		if strings.Contains(frame, "Next 7 days:") {
+			cancel()
+		}
+	}
+
+	code := ta.run([]string{"tui"})
+
+	assert.Equal(t, exitInterrupted, code)
+	assert.Empty(t, ta.stderr.String())
+	assert.True(t, strings.HasSuffix(screen.String(), leaveScreen))
+}
+
+func TestRun_TUIKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys string
+		code int
+	}{
+		// Ctrl+C is read as a key in raw mode
+		{"Ctrl+C", "\x03", exitInterrupted},
+		{"Quit", "q", exitOK},
+		{"End of input", "", exitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta, screen, _ := newTUITest(t)
+			ta.stdin = strings.NewReader(tt.keys)
+
+			code := ta.run([]string{"tui"})
+
+			assert.Equal(t, tt.code, code)
+			assert.True(t, strings.HasSuffix(screen.String(), leaveScreen))
+		})
+	}
+}
+
+func TestRun_TUIPiped(t *testing.T) {
+	ta := newTestApp("")
+	withConfig(t, ta, "")
+	require.Equal(t, exitOK, ta.run([]string{"fav", "add", "home", "New York"}))
+	ta.stdout.Reset()
+
+	code := ta.run([]string{"tui"})
+
+	// Without a terminal, the first place is shown as by sky now
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.Equal(t, "New York, US\nPartly cloudy ⛅\nTemp: 21.3°C\nFeels like: 20.1°C\n", ta.stdout.String())
+}
+
+func TestRun_TUIWithoutRawMode(t *testing.T) {
+	ta, screen, _ := newTUITest(t)
+	ta.rawInput = func() (func(), error) {
+		return nil, errors.New("raw terminal mode is not supported on this platform")
+	}
+
+	code := ta.run([]string{"tui"})
+
+	// The dashboard needs raw mode to read keys, so the first place is shown as by sky now
+	assert.Equal(t, exitOK, code, ta.stderr.String())
+	assert.NotContains(t, screen.String(), enterScreen)
+	assert.Contains(t, screen.String(), "New York, US")
+}
+
+func TestRun_TUIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		err  string
+	}{
+		{"No places", []string{"tui"}, exitUsage, "sky tui needs a location or a favorite"},
+		{"Short interval", []string{"tui", "--interval", "10s", "New York"}, exitUsage, "--interval must be at least 1m"},
+		{"Unknown place", []string{"tui", "Atlantis"}, exitNotFound, "Error: location not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+			assert.Empty(t, ta.stdout.String())
+		})
+	}
+}
+
+func TestApplyUpdate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC)
+	weather := &api.Weather{Temperature: 12}
+
+	tests := []struct {
+		name string
+		err  error
+		next time.Time
+	}{
+		{"Success", nil, now.Add(10 * time.Minute)},
+		{"Transient failure", networkFailure, now.Add(time.Minute)},
+		{"Permanent failure", &api.APIError{StatusCode: http.StatusBadRequest}, now.Add(10 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+			ta.now = func() time.Time { return now }
+			p := &tuiPlace{input: "Tokyo", weather: &api.Weather{Temperature: 8}, loading: true}
+
+			ta.applyUpdate(p, tuiUpdate{location: watchLocation, weather: weather, err: tt.err}, 10*time.Minute)
+
+			// A failed update keeps the last good reading
+			assert.False(t, p.loading)
+			assert.Equal(t, watchLocation, p.location)
+			assert.Equal(t, tt.err, p.err)
+			assert.Equal(t, tt.next, p.next)
+			if tt.err == nil {
+				assert.Equal(t, weather, p.weather)
+			} else {
+				assert.Equal(t, 8.0, p.weather.Temperature)
+			}
+		})
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"Letters", "jkrq", []string{keyDown, keyUp, keyRefresh, keyQuit}},
+		{"Arrows", "\x1b[A\x1b[B\x1bOB", []string{keyUp, keyDown, keyDown}},
+		{"Other escapes", "\x1b[C\x1b[1;5D\x1bx", nil},
+		{"Control keys", "\x10\x0e\x03", []string{keyUp, keyDown, keyInterrupt}},
+		{"Other keys", "a \r", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := make(chan string)
+			go readKeys(strings.NewReader(tt.input), keys)
+
+			var got []string
+			for key := range keys {
+				got = append(got, key)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// unitFlags holds the unit selection given on the command line
+type unitFlags struct {
+	system        string
+	temperature   string
+	windSpeed     string
+	precipitation string
+}
+
+func (f *unitFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.system, "units", "", "unit system: metric, imperial or scientific (default $SKY_UNITS or metric)")
+	fs.StringVar(&f.temperature, "temperature-unit", "", "temperature unit override: celsius, fahrenheit or kelvin")
+	fs.StringVar(&f.windSpeed, "wind-speed-unit", "", "wind speed unit override: kmh, mph, ms or kn")
+	fs.StringVar(&f.precipitation, "precipitation-unit", "", "precipitation unit override: mm or inch")
+}
+
+// resolve combines the unit system with per-quantity overrides.
+// envSystem is used when no unit system flag was given.
+func (f *unitFlags) resolve(envSystem string) (api.Units, error) {
+	units := api.MetricUnits
+
+	system := f.system
+	if system == "" {
+		system = envSystem
+	}
+	if system != "" {
+		var err error
+		if units, err = api.UnitSystem(system); err != nil {
+			return api.Units{}, err
+		}
+	}
+
+	if f.temperature != "" {
+		unit, err := api.ParseTemperatureUnit(f.temperature)
+		if err != nil {
+			return api.Units{}, err
+		}
+		units.Temperature = unit
+	}
+	if f.windSpeed != "" {
+		unit, err := api.ParseWindSpeedUnit(f.windSpeed)
+		if err != nil {
+			return api.Units{}, err
+		}
+		units.WindSpeed = unit
+	}
+	if f.precipitation != "" {
+		unit, err := api.ParsePrecipitationUnit(f.precipitation)
+		if err != nil {
+			return api.Units{}, err
+		}
+		units.Precipitation = unit
+	}
+
+	return units, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/ui"
+)
+
+const (
+	// defaultWatchInterval is the time between updates in watch mode
+	defaultWatchInterval = 10 * time.Minute
+
+	// minWatchInterval keeps watch mode from hammering the free API;
+	// Open-Meteo updates current conditions every 15 minutes anyway
+	minWatchInterval = time.Minute
+
+	// watchRetry is the longest wait before retrying a failed update
+	watchRetry = time.Minute
+
+	// watchTick is how often the status line is redrawn on a terminal
+	watchTick = time.Second
+)
+
+// Escape sequences switching to the alternate screen with a hidden cursor and back
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+// watchState is what watch mode shows between updates
+type watchState struct {
+	// reading is the last good reading, formatted, and updated when it was fetched
+	reading string
+	updated time.Time
+
+	// err is the failure of the latest update, nil once an update succeeds
+	err    error
+	failed time.Time
+
+	// next is when the next update is due
+	next time.Time
+}
+
+// runWatch shows the current conditions at the place in inputs, fetching
+// them again every --interval until interrupted
+func (a *app) runWatch(o *options, inputs []string) int {
+	if len(inputs) != 1 {
+		return a.usageError(fmt.Errorf("sky watch shows a single location"))
+	}
+	if o.interval < minWatchInterval {
+		return a.usageError(fmt.Errorf("--interval must be at least %s", ui.FormatDuration(minWatchInterval)))
+	}
+
+	// The place is looked up once; only the conditions are refreshed
+	lookup, cancel := context.WithTimeout(a.ctx, a.timeout)
+	location, err := a.resolveLocation(lookup, inputs[0])
+	cancel()
+	if err != nil {
+		return a.fail(err)
+	}
+	return a.watch(location, o)
+}
+
+// watch shows the current conditions at location until interrupted, which
+// is the expected way out and ends with exitInterrupted without an error.
+// On a terminal the screen is redrawn in place; otherwise each reading is
+// printed as it arrives. Transient failures keep the last good reading on
+// screen with a banner; other failures end the watch.
+func (a *app) watch(location *api.Location, o *options) int {
+	screen := a.terminal && !a.json
+	if screen {
+		fmt.Fprint(a.stdout, enterScreen)
+	}
+
+	s := &watchState{}
+	err := a.watchLoop(a.ctx, location, o, screen, s)
+
+	// Leave the last reading in the scrollback
+	if screen {
+		fmt.Fprint(a.stdout, leaveScreen+s.reading)
+	}
+	if err != nil {
+		return a.fail(err)
+	}
+	return exitInterrupted
+}
+
+// watchLoop updates s whenever an update is due and shows it until ctx is
+// done or an update fails for good
+func (a *app) watchLoop(ctx context.Context, location *api.Location, o *options, screen bool, s *watchState) error {
+	printed := false
+	for {
+		if !a.now().Before(s.next) {
+			if err := a.update(ctx, location, o, s); err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !screen {
+				printed = a.printUpdate(s, printed)
+			}
+		}
+
+		now := a.now()
+		wait := s.next.Sub(now)
+		if screen {
+			a.drawWatch(s, now, o.interval)
+			wait = min(wait, watchTick)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-a.after(wait):
+		}
+	}
+}
+
+// update fetches the current conditions into s and schedules the next
+// update. It returns an error only for failures that retrying cannot fix.
+func (a *app) update(ctx context.Context, location *api.Location, o *options, s *watchState) error {
+	fetch, cancel := context.WithTimeout(ctx, a.timeout)
+	weather, err := a.weather.GetWeather(fetch, location.Latitude, location.Longitude)
+	cancel()
+
+	reading := ""
+	if err == nil {
+		reading, err = a.formatWeather(location, weather, o.detail)
+	}
+
+	now := a.now()
+	switch {
+	case ctx.Err() != nil:
+		return nil
+	case err == nil:
+		s.reading, s.updated, s.err = reading, now, nil
+		s.next = now.Add(o.interval)
+	case transientFailure(err):
+		s.err, s.failed = err, now
+		s.next = now.Add(min(o.interval, watchRetry))
+	default:
+		return err
+	}
+	return nil
+}
+
+// printUpdate prints the outcome of the latest update when the output is
+// not redrawn: the reading, or the failure on stderr. It reports whether a
+// reading has been printed so far.
+func (a *app) printUpdate(s *watchState, printed bool) bool {
+	if s.err != nil {
+		a.printError(s.err, exitCode(s.err))
+		return printed
+	}
+
+	// JSON results are one per line
+	if printed && !a.json {
+		fmt.Fprintln(a.stdout)
+	}
+	fmt.Fprint(a.stdout, s.reading)
+	return true
+}
+
+// drawWatch redraws the screen with the failure banner, the last good
+// reading and the status line
+func (a *app) drawWatch(s *watchState, now time.Time, interval time.Duration) {
+	var frame strings.Builder
+	if s.err != nil {
+		frame.WriteString(ui.FormatWatchBanner(s.err, s.failed, s.next.Sub(s.failed)))
+		frame.WriteString("\n")
+	}
+	if s.reading != "" {
+		frame.WriteString(s.reading)
+		frame.WriteString("\n")
+	}
+	frame.WriteString(ui.FormatWatchStatus(s.updated, now, interval))
+
+	// Overwrite the previous frame line by line and clear what is left of it
+	fmt.Fprint(a.stdout, "\x1b[H"+strings.ReplaceAll(frame.String(), "\n", "\x1b[K\n")+"\x1b[J")
+}
+
+// transientFailure reports whether a failed update may succeed later:
+// network trouble, timeouts, rate limits, server errors and garbled responses
+func transientFailure(err error) bool {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	switch exitCode(err) {
+	case exitNetwork, exitTimeout, exitAPI:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// fakeClock advances time by each wait of watch mode and cancels the watch
+// at the limit-th wait
+type fakeClock struct {
+	now    time.Time
+	waits  []time.Duration
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (c *fakeClock) after(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	if len(c.waits) >= c.limit {
+		c.cancel()
+		return nil
+	}
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// flakyWeather is an api.WeatherProvider failing with errs[i] on the i-th call
+type flakyWeather struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyWeather) GetWeather(ctx context.Context, lat, lon float64) (*api.Weather, error) {
+	f.calls++
+	if f.calls <= len(f.errs) && f.errs[f.calls-1] != nil {
+		return nil, f.errs[f.calls-1]
+	}
+	return &api.Weather{Temperature: float64(f.calls), WeatherCodeDesc: "Clear"}, nil
+}
+
+// newWatchTest returns a test app watching with a fake clock that stops after limit waits
+func newWatchTest(limit int, errs ...error) (*testApp, *fakeClock) {
+	ta := newTestApp("")
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC), limit: limit, cancel: cancel}
+	ta.ctx = ctx
+	ta.now = func() time.Time { return clock.now }
+	ta.after = clock.after
+	ta.timeout = requestTimeout
+	ta.services.weather = &flakyWeather{errs: errs}
+	return ta, clock
+}
+
+var (
+	watchLocation  = &api.Location{Name: "Tokyo", Country: "JP", Latitude: 35.6895, Longitude: 139.6917}
+	networkFailure = &api.NetworkError{What: "weather", Err: errors.New("connection refused")}
+)
+
+func TestWatch_Piped(t *testing.T) {
+	ta, clock := newWatchTest(3, nil, networkFailure)
+
+	code := ta.watch(watchLocation, &options{interval: 10 * time.Minute})
+
+	// A failed update is retried sooner than the interval
+	assert.Equal(t, exitInterrupted, code)
+	assert.Equal(t, []time.Duration{10 * time.Minute, time.Minute, 10 * time.Minute}, clock.waits)
+	assert.Equal(t, "Tokyo, JP\nClear ☀️\nTemp: 1.0°C\nFeels like: 0.0°C\n\nTokyo, JP\nClear ☀️\nTemp: 3.0°C\nFeels like: 0.0°C\n", ta.stdout.String())
+	assert.Equal(t, 1, strings.Count(ta.stderr.String(), "Error: failed to fetch weather: connection refused"))
+}
+
+func TestWatch_PipedJSON(t *testing.T) {
+	ta, _ := newWatchTest(2)
+	ta.json = true
+
+	code := ta.watch(watchLocation, &options{interval: 10 * time.Minute})
+
+	assert.Equal(t, exitInterrupted, code)
+	lines := strings.Split(strings.TrimSpace(ta.stdout.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.NotContains(t, ta.stdout.String(), "\x1b[")
+}
+
+func TestWatch_Screen(t *testing.T) {
+	// One good update, then a failure a minute later
+	ta, _ := newWatchTest(65, nil, networkFailure)
+	ta.terminal = true
+
+	code := ta.watch(watchLocation, &options{interval: time.Minute})
+
+	assert.Equal(t, exitInterrupted, code)
+	out := ta.stdout.String()
+	assert.True(t, strings.HasPrefix(out, enterScreen))
+	assert.Empty(t, ta.stderr.String())
+
+	frames := strings.Split(strings.TrimPrefix(out, enterScreen), "\x1b[H")
+	assert.Equal(t, "Tokyo, JP\x1b[K\nClear ☀️\x1b[K\nTemp: 1.0°C\x1b[K\nFeels like: 0.0°C\x1b[K\n\x1b[K\n"+
+		"Updated just now · every 1m · Ctrl+C to quit\x1b[K\n\x1b[J", frames[1])
+
+	// The last good reading stays under the banner, and is left behind on exit
+	last := frames[len(frames)-1]
+	assert.True(t, strings.HasPrefix(last, "⚠ Update failed at 14:01, retrying in 1m: failed to fetch weather: connection refused\x1b[K\n\x1b[K\nTokyo, JP"))
+	assert.Contains(t, last, "Temp: 1.0°C")
+	assert.Contains(t, last, "Updated 1 min ago · every 1m")
+	assert.True(t, strings.HasSuffix(out, leaveScreen+"Tokyo, JP\nClear ☀️\nTemp: 1.0°C\nFeels like: 0.0°C\n"))
+}
+
+func TestWatch_ScreenWithoutReading(t *testing.T) {
+	ta, _ := newWatchTest(1, networkFailure)
+	ta.terminal = true
+
+	code := ta.watch(watchLocation, &options{interval: time.Minute})
+
+	assert.Equal(t, exitInterrupted, code)
+	assert.Contains(t, ta.stdout.String(), "⚠ Update failed at 14:00")
+	assert.Contains(t, ta.stdout.String(), "Waiting for the first reading · every 1m")
+	assert.True(t, strings.HasSuffix(ta.stdout.String(), leaveScreen))
+}
+
+func TestWatch_PermanentFailure(t *testing.T) {
+	ta, clock := newWatchTest(100, nil, &api.APIError{StatusCode: http.StatusBadRequest, Reason: "Invalid coordinates"})
+	ta.terminal = true
+
+	code := ta.watch(watchLocation, &options{interval: time.Minute})
+
+	// The screen is restored before the error is reported
+	assert.Equal(t, exitAPI, code)
+	assert.Len(t, clock.waits, 60)
+	assert.Contains(t, ta.stderr.String(), "Error: API returned status 400: Invalid coordinates")
+	assert.True(t, strings.HasSuffix(ta.stdout.String(), leaveScreen+"Tokyo, JP\nClear ☀️\nTemp: 1.0°C\nFeels like: 0.0°C\n"))
+}
+
+func TestRun_Watch(t *testing.T) {
+	ta, clock := newWatchTest(3)
+	ta.env["SKY_UNITS"] = "imperial"
+
+	code := ta.run([]string{"watch", "New York", "--interval", "15m"})
+
+	// The place is looked up once and the conditions fetched on each update
+	assert.Equal(t, exitInterrupted, code)
+	assert.Empty(t, ta.stderr.String())
+	assert.Equal(t, []string{"New York"}, ta.geocoder.queries)
+	assert.Equal(t, 3, ta.weather.calls)
+	assert.Equal(t, []time.Duration{15 * time.Minute, 15 * time.Minute, 15 * time.Minute}, clock.waits)
+	assert.Equal(t, api.ImperialUnits, ta.client.Units)
+	assert.Equal(t, 3, strings.Count(ta.stdout.String(), "New York, US\nPartly cloudy"))
+}
+
+func TestRun_WatchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		err  string
+	}{
+		{"No location", []string{"watch"}, exitUsage, "sky watch needs a location"},
+		{"Several locations", []string{"watch", "Tokyo; London"}, exitUsage, "sky watch shows a single location"},
+		{"Short interval", []string{"watch", "--interval", "30s", "New York"}, exitUsage, "--interval must be at least 1m"},
+		{"Unknown place", []string{"watch", "Atlantis"}, exitNotFound, "Error: location not found"},
+		{"JSON and art", []string{"watch", "--format", "json", "--art", "New York"}, exitUsage, "--art only works"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestApp("")
+
+			code := ta.run(tt.args)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, ta.stderr.String(), tt.err)
+			assert.Empty(t, ta.stdout.String())
+		})
+	}
+}
+
+func TestTransientFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Network", networkFailure, true},
+		{"Timeout", &api.NetworkError{What: "weather", Err: context.DeadlineExceeded}, true},
+		{"Rate limit", &api.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"Server error", &api.APIError{StatusCode: http.StatusBadGateway}, true},
+		{"Bad request", &api.APIError{StatusCode: http.StatusBadRequest}, false},
+		{"Garbled response", &api.ResponseError{Err: errors.New("unexpected EOF")}, true},
+		{"Template", errors.New("template: executing"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, transientFailure(tt.err))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !(linux || darwin)
+
+package main
+
+import "os"
+
+// terminalWidth returns 0: the terminal width is only detected on Linux and macOS,
+// elsewhere $COLUMNS or the default width is used
+func terminalWidth(f *os.File) int {
+	return 0
+}
+
+// terminalHeight returns 0: the terminal height is only detected on Linux
+// and macOS, elsewhere sky tui prints plain output
+func terminalHeight(f *os.File) int {
+	return 0
+}
+
+// notifyResize does nothing: resizes are only reported on Linux and macOS
+func notifyResize(c chan<- os.Signal) {}
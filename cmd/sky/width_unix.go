@@ -0,0 +1,40 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the width of the terminal f, or 0 when f is not a terminal
+func terminalWidth(f *os.File) int {
+	width, _ := terminalSize(f)
+	return width
+}
+
+// terminalHeight returns the height of the terminal f, or 0 when f is not a terminal
+func terminalHeight(f *os.File) int {
+	_, height := terminalSize(f)
+	return height
+}
+
+// terminalSize returns the width and height of the terminal f, or zeros
+// when f is not a terminal
+func terminalSize(f *os.File) (width, height int) {
+	var size struct {
+		rows, cols, x, y uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0, 0
+	}
+	return int(size.cols), int(size.rows)
+}
+
+// notifyResize sends on c when the terminal is resized
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// DefaultAirQualityURL is the base URL of the Open-Meteo Air Quality API
+const DefaultAirQualityURL = "https://air-quality-api.open-meteo.com"
+
+// airQualityVariables are the current conditions requested from the Air Quality API
+const airQualityVariables = "pm2_5,pm10,ozone,nitrogen_dioxide,sulphur_dioxide,carbon_monoxide,european_aqi,us_aqi," +
+	"alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,olive_pollen,ragweed_pollen"
+
+// AirQualityResponse represents the response from Open-Meteo Air Quality API
+type AirQualityResponse struct {
+	Current struct {
+		PM25            float64  `json:"pm2_5"`
+		PM10            float64  `json:"pm10"`
+		Ozone           float64  `json:"ozone"`
+		NitrogenDioxide float64  `json:"nitrogen_dioxide"`
+		SulphurDioxide  float64  `json:"sulphur_dioxide"`
+		CarbonMonoxide  float64  `json:"carbon_monoxide"`
+		EuropeanAQI     float64  `json:"european_aqi"`
+		USAQI           float64  `json:"us_aqi"`
+		AlderPollen     *float64 `json:"alder_pollen"`
+		BirchPollen     *float64 `json:"birch_pollen"`
+		GrassPollen     *float64 `json:"grass_pollen"`
+		MugwortPollen   *float64 `json:"mugwort_pollen"`
+		OlivePollen     *float64 `json:"olive_pollen"`
+		RagweedPollen   *float64 `json:"ragweed_pollen"`
+	} `json:"current"`
+}
+
+// AirQuality represents current air pollution and pollen levels.
+// Concentrations are in µg/m³.
+type AirQuality struct {
+	PM25            float64
+	PM10            float64
+	Ozone           float64
+	NitrogenDioxide float64
+	SulphurDioxide  float64
+	CarbonMonoxide  float64
+	EuropeanAQI     int
+	USAQI           int
+
+	// Pollen lists the pollen types reported for the location, in grains/m³.
+	// Open-Meteo only forecasts pollen for Europe; elsewhere it is empty.
+	Pollen []PollenCount
+
+	// FetchedAt is when the data was retrieved from the API
+	FetchedAt time.Time
+}
+
+// PollenCount is the concentration of one pollen type
+type PollenCount struct {
+	Type  string
+	Count float64
+}
+
+// AQICategory is a named band of an air quality index.
+// Level runs from 0 (best) to 5 (worst) on both indexes.
+type AQICategory struct {
+	Label string
+	Level int
+}
+
+// AirQualityProvider retrieves air quality for coordinates
+type AirQualityProvider interface {
+	GetAirQuality(ctx context.Context, lat, lon float64) (*AirQuality, error)
+}
+
+var _ AirQualityProvider = (*Client)(nil)
+
+// GetAirQuality retrieves current air quality for a given location
+func (c *Client) GetAirQuality(ctx context.Context, lat, lon float64) (*AirQuality, error) {
+	query := url.Values{}
+	query.Set("latitude", formatCoordinate(lat))
+	query.Set("longitude", formatCoordinate(lon))
+	query.Set("current", airQualityVariables)
+	query.Set("timezone", "auto")
+	apiURL := endpoint(c.AirQualityURL, DefaultAirQualityURL, "/v1/air-quality", query)
+
+	var aqResp AirQualityResponse
+	if err := c.getJSON(ctx, apiURL, "air quality", &aqResp); err != nil {
+		return nil, err
+	}
+
+	aq := aqResp.airQuality()
+	aq.FetchedAt = time.Now()
+	return aq, nil
+}
+
+// airQuality converts the current block of the response into AirQuality
+func (r *AirQualityResponse) airQuality() *AirQuality {
+	cur := r.Current
+	aq := &AirQuality{
+		PM25:            cur.PM25,
+		PM10:            cur.PM10,
+		Ozone:           cur.Ozone,
+		NitrogenDioxide: cur.NitrogenDioxide,
+		SulphurDioxide:  cur.SulphurDioxide,
+		CarbonMonoxide:  cur.CarbonMonoxide,
+		EuropeanAQI:     int(cur.EuropeanAQI + 0.5),
+		USAQI:           int(cur.USAQI + 0.5),
+	}
+
+	pollen := []struct {
+		name  string
+		count *float64
+	}{
+		{"alder", cur.AlderPollen},
+		{"birch", cur.BirchPollen},
+		{"grass", cur.GrassPollen},
+		{"mugwort", cur.MugwortPollen},
+		{"olive", cur.OlivePollen},
+		{"ragweed", cur.RagweedPollen},
+	}
+	for _, p := range pollen {
+		if p.count != nil {
+			aq.Pollen = append(aq.Pollen, PollenCount{Type: p.name, Count: *p.count})
+		}
+	}
+
+	return aq
+}
+
+// USAQICategory returns the EPA category of a US AQI value
+func USAQICategory(aqi int) AQICategory {
+	switch {
+	case aqi <= 50:
+		return AQICategory{"Good", 0}
+	case aqi <= 100:
+		return AQICategory{"Moderate", 1}
+	case aqi <= 150:
+		return AQICategory{"Unhealthy for sensitive groups", 2}
+	case aqi <= 200:
+		return AQICategory{"Unhealthy", 3}
+	case aqi <= 300:
+		return AQICategory{"Very unhealthy", 4}
+	default:
+		return AQICategory{"Hazardous", 5}
+	}
+}
+
+// EuropeanAQICategory returns the EEA category of a European AQI value
+func EuropeanAQICategory(aqi int) AQICategory {
+	switch {
+	case aqi <= 20:
+		return AQICategory{"Good", 0}
+	case aqi <= 40:
+		return AQICategory{"Fair", 1}
+	case aqi <= 60:
+		return AQICategory{"Moderate", 2}
+	case aqi <= 80:
+		return AQICategory{"Poor", 3}
+	case aqi <= 100:
+		return AQICategory{"Very poor", 4}
+	default:
+		return AQICategory{"Extremely poor", 5}
+	}
+}
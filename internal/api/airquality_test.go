@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetAirQuality(t *testing.T) {
+	var gotPath, gotCurrent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotCurrent = r.URL.Query().Get("current")
+		w.Write([]byte(`{"current":{"pm2_5":8.1,"pm10":12.4,"ozone":60.0,"nitrogen_dioxide":14.2,
+			"sulphur_dioxide":2.1,"carbon_monoxide":210.0,"european_aqi":25.4,"us_aqi":41.6,
+			"alder_pollen":null,"birch_pollen":12.0,"grass_pollen":3.5,"mugwort_pollen":null,
+			"olive_pollen":0.0,"ragweed_pollen":null}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), AirQualityURL: server.URL}
+	aq, err := client.GetAirQuality(context.Background(), 52.52, 13.405)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/air-quality", gotPath)
+	assert.Contains(t, gotCurrent, "us_aqi")
+	assert.Equal(t, 8.1, aq.PM25)
+	assert.Equal(t, 12.4, aq.PM10)
+	assert.Equal(t, 60.0, aq.Ozone)
+	assert.Equal(t, 14.2, aq.NitrogenDioxide)
+	assert.Equal(t, 2.1, aq.SulphurDioxide)
+	assert.Equal(t, 210.0, aq.CarbonMonoxide)
+	assert.Equal(t, 25, aq.EuropeanAQI)
+	assert.Equal(t, 42, aq.USAQI)
+	assert.Equal(t, []PollenCount{{"birch", 12.0}, {"grass", 3.5}, {"olive", 0}}, aq.Pollen)
+}
+
+func TestAirQualityResponse_NoPollen(t *testing.T) {
+	var resp AirQualityResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"current":{"us_aqi":160,"birch_pollen":null}}`), &resp))
+
+	aq := resp.airQuality()
+
+	assert.Empty(t, aq.Pollen)
+	assert.Equal(t, 160, aq.USAQI)
+}
+
+func TestUSAQICategory(t *testing.T) {
+	tests := []struct {
+		aqi   int
+		label string
+		level int
+	}{
+		{0, "Good", 0},
+		{50, "Good", 0},
+		{51, "Moderate", 1},
+		{150, "Unhealthy for sensitive groups", 2},
+		{151, "Unhealthy", 3},
+		{300, "Very unhealthy", 4},
+		{301, "Hazardous", 5},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, AQICategory{tt.label, tt.level}, USAQICategory(tt.aqi), "aqi=%d", tt.aqi)
+	}
+}
+
+func TestEuropeanAQICategory(t *testing.T) {
+	tests := []struct {
+		aqi   int
+		label string
+		level int
+	}{
+		{10, "Good", 0},
+		{21, "Fair", 1},
+		{60, "Moderate", 2},
+		{61, "Poor", 3},
+		{100, "Very poor", 4},
+		{101, "Extremely poor", 5},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, AQICategory{tt.label, tt.level}, EuropeanAQICategory(tt.aqi), "aqi=%d", tt.aqi)
+	}
+}
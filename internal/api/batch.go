@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Coordinates is a point on the globe in decimal degrees
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// BatchWeatherProvider retrieves current conditions for several coordinates at once
+type BatchWeatherProvider interface {
+	GetWeatherBatch(ctx context.Context, points []Coordinates) ([]*Weather, error)
+}
+
+var _ BatchWeatherProvider = (*Client)(nil)
+
+// GetWeatherBatch retrieves current weather for several locations in a single
+// request, using Open-Meteo's comma-separated latitude/longitude form.
+// The results are in the order of points.
+func (c *Client) GetWeatherBatch(ctx context.Context, points []Coordinates) ([]*Weather, error) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	lats := make([]string, len(points))
+	lons := make([]string, len(points))
+	for i, p := range points {
+		lats[i] = formatCoordinate(p.Latitude)
+		lons[i] = formatCoordinate(p.Longitude)
+	}
+
+	query := url.Values{}
+	query.Set("latitude", strings.Join(lats, ","))
+	query.Set("longitude", strings.Join(lons, ","))
+	query.Set("current", currentVariables)
+	c.Units.setQuery(query)
+	query.Set("timezone", "auto")
+	apiURL := endpoint(c.ForecastURL, DefaultForecastURL, "/v1/forecast", query)
+
+	var raw json.RawMessage
+	if err := c.getJSON(ctx, apiURL, "weather", &raw); err != nil {
+		return nil, err
+	}
+
+	// A single location is answered with an object, several with an array
+	var responses []WeatherResponse
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		if err := json.Unmarshal(raw, &responses); err != nil {
+			return nil, &ResponseError{Err: err}
+		}
+	} else {
+		responses = make([]WeatherResponse, 1)
+		if err := json.Unmarshal(raw, &responses[0]); err != nil {
+			return nil, &ResponseError{Err: err}
+		}
+	}
+	if len(responses) != len(points) {
+		return nil, &ResponseError{Err: fmt.Errorf("got %d locations, want %d", len(responses), len(points))}
+	}
+
+	fetchedAt := time.Now()
+	weathers := make([]*Weather, len(responses))
+	for i := range responses {
+		weathers[i] = responses[i].weather(c.Units)
+		weathers[i].FetchedAt = fetchedAt
+	}
+	return weathers, nil
+}
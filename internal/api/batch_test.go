@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetWeatherBatch(t *testing.T) {
+	var gotLat, gotLon string
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		gotLat = r.URL.Query().Get("latitude")
+		gotLon = r.URL.Query().Get("longitude")
+		w.Write([]byte(`[
+			{"current":{"temperature_2m":15.5,"apparent_temperature":14.2,"weather_code":3}},
+			{"current":{"temperature_2m":8.0,"apparent_temperature":5.5,"weather_code":61}}
+		]`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL, Units: ImperialUnits}
+	weathers, err := client.GetWeatherBatch(context.Background(), []Coordinates{
+		{Latitude: 35.6762, Longitude: 139.6503},
+		{Latitude: 51.5085, Longitude: -0.1257},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, "35.6762,51.5085", gotLat)
+	assert.Equal(t, "139.6503,-0.1257", gotLon)
+	require.Len(t, weathers, 2)
+	assert.Equal(t, 15.5, weathers[0].Temperature)
+	assert.Equal(t, "Slight rain", weathers[1].WeatherCodeDesc)
+	assert.Equal(t, ImperialUnits, weathers[1].Units)
+	assert.False(t, weathers[1].FetchedAt.IsZero())
+}
+
+func TestClient_GetWeatherBatch_SingleLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"current":{"temperature_2m":15.5,"apparent_temperature":14.2,"weather_code":3}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	weathers, err := client.GetWeatherBatch(context.Background(), []Coordinates{{Latitude: 1, Longitude: 2}})
+	require.NoError(t, err)
+
+	require.Len(t, weathers, 1)
+	assert.Equal(t, 15.5, weathers[0].Temperature)
+}
+
+func TestClient_GetWeatherBatch_CountMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"current":{"temperature_2m":15.5}}]`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	_, err := client.GetWeatherBatch(context.Background(), []Coordinates{{1, 2}, {3, 4}})
+
+	var respErr *ResponseError
+	assert.ErrorAs(t, err, &respErr)
+}
+
+func TestClient_GetWeatherBatch_Empty(t *testing.T) {
+	weathers, err := NewClient().GetWeatherBatch(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, weathers)
+}
@@ -1,16 +1,140 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
 const (
-	// DefaultTimeout is the timeout for HTTP requests
+	// DefaultTimeout is the timeout for a single HTTP request attempt
 	DefaultTimeout = 10 * time.Second
+
+	// DefaultGeocodingURL is the base URL of the Open-Meteo Geocoding API
+	DefaultGeocodingURL = "https://geocoding-api.open-meteo.com"
+
+	// DefaultForecastURL is the base URL of the Open-Meteo Forecast API
+	DefaultForecastURL = "https://api.open-meteo.com"
 )
 
-// DefaultClient is the HTTP client used for API requests
+// DefaultClient is the HTTP client used for API requests.
+// It retries transient failures; each attempt times out after DefaultTimeout.
 var DefaultClient = &http.Client{
-	Timeout: DefaultTimeout,
+	Transport: &RetryTransport{AttemptTimeout: DefaultTimeout},
+}
+
+// Geocoder resolves place names to geographic locations
+type Geocoder interface {
+	GetLocation(ctx context.Context, city string) (*Location, error)
+}
+
+// WeatherProvider retrieves current weather conditions for coordinates
+type WeatherProvider interface {
+	GetWeather(ctx context.Context, lat, lon float64) (*Weather, error)
+}
+
+// Client is an Open-Meteo API client.
+// The zero value uses DefaultClient and the public Open-Meteo endpoints.
+type Client struct {
+	// HTTPClient performs the requests; DefaultClient is used when nil
+	HTTPClient *http.Client
+
+	// GeocodingURL is the base URL of the geocoding service
+	GeocodingURL string
+
+	// ForecastURL is the base URL of the forecast service
+	ForecastURL string
+
+	// AirQualityURL is the base URL of the air quality service
+	AirQualityURL string
+
+	// ReverseGeocodingURL is the base URL of an optional Nominatim-compatible
+	// reverse geocoding service; the embedded gazetteer is used when empty
+	ReverseGeocodingURL string
+
+	// Units selects the units of returned measurements; empty fields are metric
+	Units Units
+
+	// Language is the ISO 639-1 code of the language of place names;
+	// English when empty
+	Language string
+}
+
+var (
+	_ Geocoder        = (*Client)(nil)
+	_ WeatherProvider = (*Client)(nil)
+)
+
+// NewClient returns a Client configured for the public Open-Meteo endpoints
+func NewClient() *Client {
+	return &Client{
+		HTTPClient:    DefaultClient,
+		GeocodingURL:  DefaultGeocodingURL,
+		ForecastURL:   DefaultForecastURL,
+		AirQualityURL: DefaultAirQualityURL,
+		Units:         MetricUnits,
+	}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return DefaultClient
+}
+
+func (c *Client) language() string {
+	if c.Language != "" {
+		return c.Language
+	}
+	return "en"
+}
+
+// endpoint builds a request URL from a base URL, a path and query parameters.
+// def is used when base is empty.
+func endpoint(base, def, path string, query url.Values) string {
+	if base == "" {
+		base = def
+	}
+	return strings.TrimRight(base, "/") + path + "?" + query.Encode()
+}
+
+// getJSON performs a GET request and decodes the JSON response body into v.
+// what names the fetched resource in error messages.
+func (c *Client) getJSON(ctx context.Context, apiURL, what string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return &NetworkError{What: what, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &NetworkError{What: what, Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Open-Meteo explains failures as {"error": true, "reason": "..."}
+		var errResp struct {
+			Reason string `json:"reason"`
+		}
+		json.Unmarshal(body, &errResp)
+		return &APIError{StatusCode: resp.StatusCode, Reason: errResp.Reason}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return &ResponseError{Err: err}
+	}
+
+	return nil
 }
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient_Defaults(t *testing.T) {
+	client := NewClient()
+
+	assert.Same(t, DefaultClient, client.HTTPClient)
+	assert.Equal(t, DefaultGeocodingURL, client.GeocodingURL)
+	assert.Equal(t, DefaultForecastURL, client.ForecastURL)
+}
+
+func TestEndpoint(t *testing.T) {
+	query := url.Values{}
+	query.Set("name", "New York")
+
+	tests := []struct {
+		name     string
+		base     string
+		expected string
+	}{
+		{"Default base", "", "https://geocoding-api.open-meteo.com/v1/search?name=New+York"},
+		{"Custom base", "http://meteo.internal:8080", "http://meteo.internal:8080/v1/search?name=New+York"},
+		{"Trailing slash", "http://meteo.internal/", "http://meteo.internal/v1/search?name=New+York"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, endpoint(tt.base, DefaultGeocodingURL, "/v1/search", query))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultForecastDays is the number of days requested when none is given
+	DefaultForecastDays = 7
+
+	// MaxForecastDays is the longest daily forecast Open-Meteo provides
+	MaxForecastDays = 16
+)
+
+// DailyResponse represents the daily block of the Open-Meteo Weather API response
+type DailyResponse struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Daily            struct {
+		Time                     []string  `json:"time"`
+		TemperatureMax           []float64 `json:"temperature_2m_max"`
+		TemperatureMin           []float64 `json:"temperature_2m_min"`
+		PrecipitationSum         []float64 `json:"precipitation_sum"`
+		PrecipitationProbability []int     `json:"precipitation_probability_max"`
+		WeatherCode              []int     `json:"weather_code"`
+		Sunrise                  []string  `json:"sunrise"`
+		Sunset                   []string  `json:"sunset"`
+		WindSpeedMax             []float64 `json:"wind_speed_10m_max"`
+	} `json:"daily"`
+}
+
+// DailyWeather represents forecast conditions for a single day
+type DailyWeather struct {
+	Date                     time.Time
+	TemperatureMax           float64
+	TemperatureMin           float64
+	PrecipitationSum         float64
+	PrecipitationProbability int
+	WeatherCode              int
+	WeatherCodeDesc          string
+	Sunrise                  time.Time
+	Sunset                   time.Time
+	WindSpeedMax             float64
+}
+
+// DailyForecast represents a day-by-day forecast in the location's local time
+type DailyForecast struct {
+	Days  []DailyWeather
+	Units Units
+
+	// FetchedAt is when the forecast was retrieved from the API
+	FetchedAt time.Time
+}
+
+// GetDailyForecast retrieves the forecast for the next days, starting with today
+func (c *Client) GetDailyForecast(ctx context.Context, lat, lon float64, days int) (*DailyForecast, error) {
+	if days < 1 || days > MaxForecastDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", MaxForecastDays)
+	}
+
+	query := url.Values{}
+	query.Set("latitude", formatCoordinate(lat))
+	query.Set("longitude", formatCoordinate(lon))
+	query.Set("daily", "temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,weather_code,sunrise,sunset,wind_speed_10m_max")
+	query.Set("forecast_days", strconv.Itoa(days))
+	c.Units.setQuery(query)
+	query.Set("timezone", "auto")
+	apiURL := endpoint(c.ForecastURL, DefaultForecastURL, "/v1/forecast", query)
+
+	var dailyResp DailyResponse
+	if err := c.getJSON(ctx, apiURL, "forecast", &dailyResp); err != nil {
+		return nil, err
+	}
+
+	forecast, err := dailyResp.forecast(c.Units)
+	if err != nil {
+		return nil, err
+	}
+	forecast.FetchedAt = time.Now()
+	return forecast, nil
+}
+
+// forecast converts the column-oriented response into per-day values
+func (r *DailyResponse) forecast(units Units) (*DailyForecast, error) {
+	zone := time.FixedZone("", r.UTCOffsetSeconds)
+	d := r.Daily
+
+	forecast := &DailyForecast{Days: make([]DailyWeather, 0, len(d.Time)), Units: units}
+	for i, ts := range d.Time {
+		date, err := time.ParseInLocation("2006-01-02", ts, zone)
+		if err != nil {
+			return nil, &ResponseError{Err: err}
+		}
+
+		day := DailyWeather{
+			Date:                     date,
+			TemperatureMax:           units.temperature(at(d.TemperatureMax, i)),
+			TemperatureMin:           units.temperature(at(d.TemperatureMin, i)),
+			PrecipitationSum:         at(d.PrecipitationSum, i),
+			PrecipitationProbability: at(d.PrecipitationProbability, i),
+			WeatherCode:              at(d.WeatherCode, i),
+			WindSpeedMax:             at(d.WindSpeedMax, i),
+		}
+		day.WeatherCodeDesc = WeatherCodeDescription(day.WeatherCode)
+
+		// Polar day and night have no sunrise or sunset
+		if s := at(d.Sunrise, i); s != "" {
+			if day.Sunrise, err = parseLocalTime(s, zone); err != nil {
+				return nil, &ResponseError{Err: err}
+			}
+		}
+		if s := at(d.Sunset, i); s != "" {
+			if day.Sunset, err = parseLocalTime(s, zone); err != nil {
+				return nil, &ResponseError{Err: err}
+			}
+		}
+
+		forecast.Days = append(forecast.Days, day)
+	}
+
+	return forecast, nil
+}
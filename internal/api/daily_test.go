@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dailyJSON = `{
+	"utc_offset_seconds": 3600,
+	"daily": {
+		"time": ["2025-03-01", "2025-03-02"],
+		"temperature_2m_max": [12.3, 9.8],
+		"temperature_2m_min": [4.1, -1.5],
+		"precipitation_sum": [0.0, 6.4],
+		"precipitation_probability_max": [10, 85],
+		"weather_code": [2, 63],
+		"sunrise": ["2025-03-01T06:58", "2025-03-02T06:56"],
+		"sunset": ["2025-03-01T17:53", "2025-03-02T17:55"],
+		"wind_speed_10m_max": [18.2, 31.0]
+	}
+}`
+
+func TestDailyResponse_Forecast(t *testing.T) {
+	var resp DailyResponse
+	require.NoError(t, json.Unmarshal([]byte(dailyJSON), &resp))
+
+	forecast, err := resp.forecast(MetricUnits)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 2)
+
+	day := forecast.Days[1]
+	assert.Equal(t, "2025-03-02", day.Date.Format("2006-01-02"))
+	assert.Equal(t, 9.8, day.TemperatureMax)
+	assert.Equal(t, -1.5, day.TemperatureMin)
+	assert.Equal(t, 6.4, day.PrecipitationSum)
+	assert.Equal(t, 85, day.PrecipitationProbability)
+	assert.Equal(t, 63, day.WeatherCode)
+	assert.Equal(t, "Moderate rain", day.WeatherCodeDesc)
+	assert.Equal(t, "06:56", day.Sunrise.Format("15:04"))
+	assert.Equal(t, "17:55", day.Sunset.Format("15:04"))
+	assert.Equal(t, 31.0, day.WindSpeedMax)
+}
+
+func TestDailyResponse_PolarNight(t *testing.T) {
+	var resp DailyResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"daily":{"time":["2025-12-21"],"sunrise":[null],"sunset":[null]}}`), &resp))
+
+	forecast, err := resp.forecast(MetricUnits)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 1)
+	assert.True(t, forecast.Days[0].Sunrise.IsZero())
+	assert.True(t, forecast.Days[0].Sunset.IsZero())
+}
+
+func TestDailyResponse_InvalidDate(t *testing.T) {
+	var resp DailyResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"daily":{"time":["03/01/2025"]}}`), &resp))
+
+	_, err := resp.forecast(MetricUnits)
+	assert.Error(t, err)
+}
+
+func TestClient_GetDailyForecast(t *testing.T) {
+	var gotDays, gotDaily string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotDays = r.URL.Query().Get("forecast_days")
+		gotDaily = r.URL.Query().Get("daily")
+		w.Write([]byte(dailyJSON))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	forecast, err := client.GetDailyForecast(context.Background(), 52.52, 13.405, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2", gotDays)
+	assert.Contains(t, gotDaily, "sunrise")
+	assert.Len(t, forecast.Days, 2)
+}
+
+func TestClient_GetDailyForecast_InvalidDays(t *testing.T) {
+	client := NewClient()
+
+	for _, days := range []int{0, MaxForecastDays + 1} {
+		_, err := client.GetDailyForecast(context.Background(), 0, 0, days)
+		assert.Error(t, err, "days=%d", days)
+	}
+}
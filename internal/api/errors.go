@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+var (
+	// ErrLocationNotFound is returned when the geocoder has no match for a place name
+	ErrLocationNotFound = errors.New("location not found")
+
+	// ErrTimeout matches NetworkErrors caused by a request timing out
+	ErrTimeout = errors.New("request timed out")
+)
+
+// APIError is returned when a service answers with a non-200 status
+type APIError struct {
+	StatusCode int
+
+	// Reason is the explanation from Open-Meteo's JSON error body, if any
+	Reason string
+}
+
+func (e *APIError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Reason)
+	}
+	return fmt.Sprintf("API returned status %d", e.StatusCode)
+}
+
+// NetworkError is returned when a request fails before a response is received
+type NetworkError struct {
+	// What names the fetched resource, e.g. "location" or "weather"
+	What string
+	Err  error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("failed to fetch %s: %v", e.What, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrTimeout) report timeouts
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrTimeout && e.Timeout()
+}
+
+// Timeout reports whether the request failed because a deadline passed
+func (e *NetworkError) Timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+// ResponseError is returned when a response body cannot be decoded
+type ResponseError struct {
+	Err error
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("failed to parse response: %v", e.Err)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_APIErrorReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":true,"reason":"Latitude must be in range of -90 to 90°. Given: 91.0."}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	_, err := client.GetWeather(context.Background(), 91, 0)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "Latitude must be in range of -90 to 90°. Given: 91.0.", apiErr.Reason)
+	assert.Equal(t, "API returned status 400: Latitude must be in range of -90 to 90°. Given: 91.0.", err.Error())
+}
+
+func TestClient_APIErrorWithoutReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`<html>Bad Gateway</html>`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), GeocodingURL: server.URL}
+	_, err := client.GetLocation(context.Background(), "Berlin")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "API returned status 502", err.Error())
+}
+
+func TestClient_LocationNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"generationtime_ms":0.5}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), GeocodingURL: server.URL}
+	_, err := client.GetLocation(context.Background(), "Atlantis")
+
+	assert.ErrorIs(t, err, ErrLocationNotFound)
+}
+
+func TestClient_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	_, err := client.GetWeather(context.Background(), 0, 0)
+
+	var netErr *NetworkError
+	require.ErrorAs(t, err, &netErr)
+	assert.Equal(t, "weather", netErr.What)
+	assert.False(t, netErr.Timeout())
+	assert.NotErrorIs(t, err, ErrTimeout)
+}
+
+func TestClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	_, err := client.GetWeather(ctx, 0, 0)
+
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_ResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"current":`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	_, err := client.GetWeather(context.Background(), 0, 0)
+
+	var respErr *ResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Contains(t, err.Error(), "failed to parse response")
+}
+
+func TestNetworkError_TimeoutFromTransport(t *testing.T) {
+	err := &NetworkError{What: "location", Err: timeoutError{}}
+
+	assert.True(t, err.Timeout())
+	assert.True(t, errors.Is(err, ErrTimeout))
+}
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -2,20 +2,23 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
+	"strconv"
 )
 
 // GeocodingResponse represents the response from Open-Meteo Geocoding API
 type GeocodingResponse struct {
 	Results []struct {
-		Name      string  `json:"name"`
-		Latitude  float64 `json:"latitude"`
-		Longitude float64 `json:"longitude"`
-		Country   string  `json:"country_code"`
+		Name        string  `json:"name"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		Country     string  `json:"country_code"`
+		Admin1      string  `json:"admin1"`
+		Admin2      string  `json:"admin2"`
+		Population  int     `json:"population"`
+		Timezone    string  `json:"timezone"`
+		FeatureCode string  `json:"feature_code"`
 	} `json:"results"`
 }
 
@@ -25,47 +28,77 @@ type Location struct {
 	Latitude  float64
 	Longitude float64
 	Country   string
+
+	Admin1      string // first-level administrative area, e.g. state
+	Admin2      string // second-level administrative area, e.g. county
+	Population  int
+	Timezone    string
+	FeatureCode string // GeoNames feature code, e.g. PPLC for a capital
+
+	// Distance is set for reverse-geocoded coordinates: the distance
+	// in kilometers from the coordinates to the named place
+	Distance float64
 }
 
-// GetLocation retrieves coordinates for a given city name
-func GetLocation(ctx context.Context, city string) (*Location, error) {
-	encodedCity := url.QueryEscape(city)
-	apiURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1&language=en&format=json", encodedCity)
+const (
+	// DefaultSearchCount is the number of candidates returned by a location search
+	DefaultSearchCount = 10
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	// MaxSearchCount is the largest number of candidates the Geocoding API returns
+	MaxSearchCount = 100
+)
+
+// LocationSearcher finds candidate locations matching a place name
+type LocationSearcher interface {
+	SearchLocations(ctx context.Context, name string, count int) ([]Location, error)
+}
+
+var _ LocationSearcher = (*Client)(nil)
 
-	resp, err := DefaultClient.Do(req)
+// GetLocation retrieves coordinates for a given city name
+func (c *Client) GetLocation(ctx context.Context, city string) (*Location, error) {
+	locations, err := c.SearchLocations(ctx, city, 1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch location: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &locations[0], nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+// SearchLocations retrieves up to count locations matching name, best match first
+func (c *Client) SearchLocations(ctx context.Context, name string, count int) ([]Location, error) {
+	if count < 1 || count > MaxSearchCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", MaxSearchCount)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("count", strconv.Itoa(count))
+	query.Set("language", c.language())
+	query.Set("format", "json")
+	apiURL := endpoint(c.GeocodingURL, DefaultGeocodingURL, "/v1/search", query)
 
 	var geoResp GeocodingResponse
-	if err := json.Unmarshal(body, &geoResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := c.getJSON(ctx, apiURL, "location", &geoResp); err != nil {
+		return nil, err
 	}
 
 	if len(geoResp.Results) == 0 {
-		return nil, fmt.Errorf("location not found")
+		return nil, ErrLocationNotFound
 	}
 
-	result := geoResp.Results[0]
-	return &Location{
-		Name:      result.Name,
-		Latitude:  result.Latitude,
-		Longitude: result.Longitude,
-		Country:   result.Country,
-	}, nil
+	locations := make([]Location, 0, len(geoResp.Results))
+	for _, result := range geoResp.Results {
+		locations = append(locations, Location{
+			Name:        result.Name,
+			Latitude:    result.Latitude,
+			Longitude:   result.Longitude,
+			Country:     result.Country,
+			Admin1:      result.Admin1,
+			Admin2:      result.Admin2,
+			Population:  result.Population,
+			Timezone:    result.Timezone,
+			FeatureCode: result.FeatureCode,
+		})
+	}
+	return locations, nil
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -294,3 +295,147 @@ func TestGetLocation_MockServer(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetLocation(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseCode  int
+		responseBody  string
+		expectedError string
+		expectedName  string
+	}{
+		{
+			name:         "Successful response",
+			responseCode: 200,
+			responseBody: `{"results":[{"name":"Berlin","latitude":52.52,"longitude":13.405,"country_code":"DE"}]}`,
+			expectedName: "Berlin",
+		},
+		{
+			name:          "Location not found",
+			responseCode:  200,
+			responseBody:  `{"results":[]}`,
+			expectedError: "location not found",
+		},
+		{
+			name:          "Server error",
+			responseCode:  500,
+			responseBody:  `{"error":"Internal server error"}`,
+			expectedError: "API returned status 500",
+		},
+		{
+			name:          "Invalid JSON",
+			responseCode:  200,
+			responseBody:  `{invalid}`,
+			expectedError: "failed to parse response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotName string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotName = r.URL.Query().Get("name")
+				w.WriteHeader(tt.responseCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := &Client{HTTPClient: server.Client(), GeocodingURL: server.URL}
+			location, err := client.GetLocation(context.Background(), "São Paulo")
+
+			assert.Equal(t, "/v1/search", gotPath)
+			assert.Equal(t, "São Paulo", gotName)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, location.Name)
+		})
+	}
+}
+
+func TestClient_SearchLocations(t *testing.T) {
+	var gotCount string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCount = r.URL.Query().Get("count")
+		w.Write([]byte(`{"results":[
+			{"name":"Paris","latitude":48.85341,"longitude":2.3488,"country_code":"FR","admin1":"Île-de-France","admin2":"Paris","population":2138551,"timezone":"Europe/Paris","feature_code":"PPLC"},
+			{"name":"Paris","latitude":33.66094,"longitude":-95.55551,"country_code":"US","admin1":"Texas","admin2":"Lamar","population":24782,"timezone":"America/Chicago","feature_code":"PPLA2"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), GeocodingURL: server.URL}
+	locations, err := client.SearchLocations(context.Background(), "Paris", 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "5", gotCount)
+	require.Len(t, locations, 2)
+	assert.Equal(t, Location{
+		Name:        "Paris",
+		Latitude:    33.66094,
+		Longitude:   -95.55551,
+		Country:     "US",
+		Admin1:      "Texas",
+		Admin2:      "Lamar",
+		Population:  24782,
+		Timezone:    "America/Chicago",
+		FeatureCode: "PPLA2",
+	}, locations[1])
+}
+
+func TestClient_SearchLocations_InvalidCount(t *testing.T) {
+	client := NewClient()
+
+	for _, count := range []int{0, MaxSearchCount + 1} {
+		_, err := client.SearchLocations(context.Background(), "Paris", count)
+		assert.Error(t, err, "count=%d", count)
+	}
+}
+
+func TestClient_GetLocation_RequestsSingleResult(t *testing.T) {
+	var gotCount string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCount = r.URL.Query().Get("count")
+		w.Write([]byte(`{"results":[{"name":"Springfield","country_code":"US","admin1":"Illinois"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), GeocodingURL: server.URL}
+	location, err := client.GetLocation(context.Background(), "Springfield")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1", gotCount)
+	assert.Equal(t, "Illinois", location.Admin1)
+}
+
+func TestClient_SearchLocations_Language(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{"English by default", "", "en"},
+		{"Configured", "de", "de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLanguage string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotLanguage = r.URL.Query().Get("language")
+				w.Write([]byte(`{"results":[{"name":"München","country_code":"DE"}]}`))
+			}))
+			defer server.Close()
+
+			client := &Client{HTTPClient: server.Client(), GeocodingURL: server.URL, Language: tt.language}
+			_, err := client.SearchLocations(context.Background(), "Munich", 1)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, gotLanguage)
+		})
+	}
+}
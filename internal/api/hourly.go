@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultForecastHours is the number of hours requested when none is given
+	DefaultForecastHours = 24
+
+	// MaxForecastHours is the longest hourly forecast Open-Meteo provides (16 days)
+	MaxForecastHours = 16 * 24
+)
+
+// HourlyResponse represents the hourly block of the Open-Meteo Weather API response
+type HourlyResponse struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time                     []string  `json:"time"`
+		Temperature              []float64 `json:"temperature_2m"`
+		ApparentTemp             []float64 `json:"apparent_temperature"`
+		PrecipitationProbability []int     `json:"precipitation_probability"`
+		WeatherCode              []int     `json:"weather_code"`
+		WindSpeed                []float64 `json:"wind_speed_10m"`
+		WindDirection            []int     `json:"wind_direction_10m"`
+	} `json:"hourly"`
+}
+
+// HourlyWeather represents forecast conditions for a single hour
+type HourlyWeather struct {
+	Time                     time.Time
+	Temperature              float64
+	ApparentTemp             float64
+	PrecipitationProbability int
+	WeatherCode              int
+	WeatherCodeDesc          string
+	WindSpeed                float64
+	WindDirection            int
+}
+
+// HourlyForecast represents an hour-by-hour forecast in the location's local time
+type HourlyForecast struct {
+	Hours []HourlyWeather
+	Units Units
+
+	// FetchedAt is when the forecast was retrieved from the API
+	FetchedAt time.Time
+}
+
+// ForecastProvider retrieves forecasts for coordinates
+type ForecastProvider interface {
+	GetHourlyForecast(ctx context.Context, lat, lon float64, hours int) (*HourlyForecast, error)
+	GetDailyForecast(ctx context.Context, lat, lon float64, days int) (*DailyForecast, error)
+}
+
+var _ ForecastProvider = (*Client)(nil)
+
+// GetHourlyForecast retrieves the forecast for the next hours, starting with the current hour
+func (c *Client) GetHourlyForecast(ctx context.Context, lat, lon float64, hours int) (*HourlyForecast, error) {
+	if hours < 1 || hours > MaxForecastHours {
+		return nil, fmt.Errorf("hours must be between 1 and %d", MaxForecastHours)
+	}
+
+	query := url.Values{}
+	query.Set("latitude", formatCoordinate(lat))
+	query.Set("longitude", formatCoordinate(lon))
+	query.Set("hourly", "temperature_2m,apparent_temperature,precipitation_probability,weather_code,wind_speed_10m,wind_direction_10m")
+	query.Set("forecast_hours", strconv.Itoa(hours))
+	c.Units.setQuery(query)
+	query.Set("timezone", "auto")
+	apiURL := endpoint(c.ForecastURL, DefaultForecastURL, "/v1/forecast", query)
+
+	var hourlyResp HourlyResponse
+	if err := c.getJSON(ctx, apiURL, "forecast", &hourlyResp); err != nil {
+		return nil, err
+	}
+
+	forecast, err := hourlyResp.forecast(c.Units)
+	if err != nil {
+		return nil, err
+	}
+	forecast.FetchedAt = time.Now()
+	return forecast, nil
+}
+
+// forecast converts the column-oriented response into per-hour values
+func (r *HourlyResponse) forecast(units Units) (*HourlyForecast, error) {
+	zone := time.FixedZone("", r.UTCOffsetSeconds)
+	h := r.Hourly
+
+	forecast := &HourlyForecast{Hours: make([]HourlyWeather, 0, len(h.Time)), Units: units}
+	for i, ts := range h.Time {
+		t, err := parseLocalTime(ts, zone)
+		if err != nil {
+			return nil, &ResponseError{Err: err}
+		}
+
+		hour := HourlyWeather{
+			Time:                     t,
+			Temperature:              units.temperature(at(h.Temperature, i)),
+			ApparentTemp:             units.temperature(at(h.ApparentTemp, i)),
+			PrecipitationProbability: at(h.PrecipitationProbability, i),
+			WeatherCode:              at(h.WeatherCode, i),
+			WindSpeed:                at(h.WindSpeed, i),
+			WindDirection:            at(h.WindDirection, i),
+		}
+		hour.WeatherCodeDesc = WeatherCodeDescription(hour.WeatherCode)
+		forecast.Hours = append(forecast.Hours, hour)
+	}
+
+	return forecast, nil
+}
+
+// parseLocalTime parses an Open-Meteo ISO 8601 local timestamp without offset
+func parseLocalTime(s string, zone *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02T15:04", s, zone)
+}
+
+// at returns values[i], or the zero value when the series is shorter than expected
+func at[T any](values []T, i int) T {
+	var zero T
+	if i < len(values) {
+		return values[i]
+	}
+	return zero
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hourlyJSON = `{
+	"utc_offset_seconds": 32400,
+	"hourly": {
+		"time": ["2025-03-01T21:00", "2025-03-01T22:00"],
+		"temperature_2m": [8.4, 7.9],
+		"apparent_temperature": [5.1, 4.6],
+		"precipitation_probability": [15, 70],
+		"weather_code": [3, 61],
+		"wind_speed_10m": [11.2, 14.8],
+		"wind_direction_10m": [200, 215]
+	}
+}`
+
+func TestHourlyResponse_Forecast(t *testing.T) {
+	var resp HourlyResponse
+	require.NoError(t, json.Unmarshal([]byte(hourlyJSON), &resp))
+
+	forecast, err := resp.forecast(MetricUnits)
+	require.NoError(t, err)
+	require.Len(t, forecast.Hours, 2)
+
+	second := forecast.Hours[1]
+	assert.Equal(t, 22, second.Time.Hour())
+	_, offset := second.Time.Zone()
+	assert.Equal(t, 9*60*60, offset)
+	assert.Equal(t, time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC), second.Time.UTC())
+	assert.Equal(t, 7.9, second.Temperature)
+	assert.Equal(t, 4.6, second.ApparentTemp)
+	assert.Equal(t, 70, second.PrecipitationProbability)
+	assert.Equal(t, 61, second.WeatherCode)
+	assert.Equal(t, "Slight rain", second.WeatherCodeDesc)
+	assert.Equal(t, 14.8, second.WindSpeed)
+	assert.Equal(t, 215, second.WindDirection)
+}
+
+func TestHourlyResponse_ShortSeries(t *testing.T) {
+	// Missing or truncated series must not panic
+	var resp HourlyResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"hourly":{"time":["2025-03-01T21:00"],"temperature_2m":[]}}`), &resp))
+
+	forecast, err := resp.forecast(MetricUnits)
+	require.NoError(t, err)
+	require.Len(t, forecast.Hours, 1)
+	assert.Equal(t, 0.0, forecast.Hours[0].Temperature)
+}
+
+func TestHourlyResponse_InvalidTime(t *testing.T) {
+	var resp HourlyResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"hourly":{"time":["yesterday"]}}`), &resp))
+
+	_, err := resp.forecast(MetricUnits)
+	assert.Error(t, err)
+}
+
+func TestClient_GetHourlyForecast(t *testing.T) {
+	var gotHours, gotHourly string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHours = r.URL.Query().Get("forecast_hours")
+		gotHourly = r.URL.Query().Get("hourly")
+		w.Write([]byte(hourlyJSON))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	forecast, err := client.GetHourlyForecast(context.Background(), 35.6762, 139.6503, 12)
+	require.NoError(t, err)
+
+	assert.Equal(t, "12", gotHours)
+	assert.Contains(t, gotHourly, "precipitation_probability")
+	assert.Len(t, forecast.Hours, 2)
+}
+
+func TestClient_GetHourlyForecast_InvalidHours(t *testing.T) {
+	client := NewClient()
+
+	for _, hours := range []int{0, -1, MaxForecastHours + 1} {
+		_, err := client.GetHourlyForecast(context.Background(), 0, 0, hours)
+		assert.Error(t, err, "hours=%d", hours)
+	}
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed request is retried
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBaseDelay is the backoff before the first retry; it doubles on each retry
+	DefaultRetryBaseDelay = 250 * time.Millisecond
+
+	// DefaultRetryMaxDelay caps a single backoff, including one asked for by Retry-After
+	DefaultRetryMaxDelay = 5 * time.Second
+)
+
+// RetryTransport is an http.RoundTripper that retries idempotent requests
+// failing with 429, a 5xx status or a transient network error.
+// Retries back off exponentially with jitter, honor Retry-After, and stop
+// when the backoff would run past the request's context deadline.
+// Zero fields use the defaults.
+type RetryTransport struct {
+	// Base performs each attempt; http.DefaultTransport is used when nil
+	Base http.RoundTripper
+
+	// MaxRetries is the number of retries after the first attempt.
+	// Zero uses DefaultMaxRetries; a negative value turns retries off.
+	MaxRetries int
+
+	// BaseDelay is the backoff before the first retry
+	BaseDelay time.Duration
+
+	// MaxDelay caps a single backoff. A longer Retry-After is not waited for.
+	MaxDelay time.Duration
+
+	// AttemptTimeout bounds each attempt, so a hung connection leaves time to retry
+	AttemptTimeout time.Duration
+
+	// sleep waits between attempts; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !idempotent(req) {
+		return t.base().RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req, attempt)
+		if attempt >= t.maxRetries() || ctx.Err() != nil || !retryable(resp, err) {
+			return resp, err
+		}
+
+		// Waiting past the deadline would only end in a timeout
+		delay, ok := t.delay(attempt, resp)
+		if deadline, has := ctx.Deadline(); has && time.Now().Add(delay).After(deadline) {
+			ok = false
+		}
+		if !ok {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := t.wait(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt performs try number n of req under AttemptTimeout. Retries send
+// a fresh copy of the body, as the first try has consumed req.Body.
+func (t *RetryTransport) attempt(req *http.Request, n int) (*http.Response, error) {
+	timeout := t.AttemptTimeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+
+	try := req.Clone(ctx)
+	if n > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		try.Body = body
+	}
+
+	resp, err := t.base().RoundTrip(try)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The attempt lasts until its body has been read
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// delay returns the backoff before retry number attempt+1, and false when
+// the server asked to wait longer than MaxDelay
+func (t *RetryTransport) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	base, limit := t.BaseDelay, t.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	if limit <= 0 {
+		limit = DefaultRetryMaxDelay
+	}
+
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d, d <= limit
+		}
+	}
+
+	// Equal jitter: half the backoff is fixed, the other half random
+	backoff := min(base<<attempt, limit)
+	return backoff/2 + rand.N(backoff/2+1), true
+}
+
+func (t *RetryTransport) wait(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *RetryTransport) maxRetries() int {
+	switch {
+	case t.MaxRetries < 0:
+		return 0
+	case t.MaxRetries == 0:
+		return DefaultMaxRetries
+	}
+	return t.MaxRetries
+}
+
+// idempotent reports whether req may safely be sent more than once.
+// A body must be reproducible with GetBody to be sent again.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+// retryable reports whether an attempt failed in a way that may succeed on retry
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return transientError(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// transientError reports whether a transport error is worth retrying.
+// Unknown hosts and certificate failures will not fix themselves.
+func transientError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// cancelOnClose releases an attempt's context once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRetryClient returns an HTTP client retrying against server and recording its waits
+func newRetryClient(server *httptest.Server, waits *[]time.Duration) *http.Client {
+	return &http.Client{Transport: &RetryTransport{
+		Base:      server.Client().Transport,
+		BaseDelay: 100 * time.Millisecond,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*waits = append(*waits, d)
+			return nil
+		},
+	}}
+}
+
+func TestRetryTransport_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"current":{"temperature_2m":18.5,"apparent_temperature":17.0,"weather_code":0}}`))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &Client{HTTPClient: newRetryClient(server, &waits), ForecastURL: server.URL}
+	weather, err := client.GetWeather(context.Background(), 52.52, 13.41)
+
+	require.NoError(t, err)
+	assert.Equal(t, 18.5, weather.Temperature)
+	assert.Equal(t, int32(3), calls.Load())
+	require.Len(t, waits, 2)
+	// Backoff doubles, with up to half of it randomized
+	assert.InDelta(t, 75*time.Millisecond, waits[0], float64(25*time.Millisecond))
+	assert.InDelta(t, 150*time.Millisecond, waits[1], float64(50*time.Millisecond))
+}
+
+func TestRetryTransport_GivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &Client{HTTPClient: newRetryClient(server, &waits), GeocodingURL: server.URL}
+	_, err := client.GetLocation(context.Background(), "Berlin")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, int32(DefaultMaxRetries+1), calls.Load())
+}
+
+func TestRetryTransport_NoRetryOnClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotImplemented} {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(status)
+		}))
+
+		var waits []time.Duration
+		resp, err := newRetryClient(server, &waits).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		server.Close()
+
+		assert.Equal(t, status, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load(), "status %d", status)
+	}
+}
+
+func TestRetryTransport_NoRetryForNonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	resp, err := newRetryClient(server, &waits).Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryTransport_ResendsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	req, err := http.NewRequest(http.MethodGet, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := newRetryClient(server, &waits).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+}
+
+func TestRetryTransport_MaxRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		calls      int32
+	}{
+		{"Default", 0, DefaultMaxRetries + 1},
+		{"One", 1, 2},
+		{"Off", -1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			client := newRetryClient(server, &waits)
+			client.Transport.(*RetryTransport).MaxRetries = tt.maxRetries
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.calls, calls.Load())
+		})
+	}
+}
+
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`ok`))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	resp, err := newRetryClient(server, &waits).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{2 * time.Second}, waits)
+}
+
+func TestRetryTransport_RetryAfterTooLong(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	resp, err := newRetryClient(server, &waits).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Empty(t, waits)
+}
+
+func TestRetryTransport_RespectsDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// The retry would start after the deadline, so the first response is final
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var waits []time.Duration
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := newRetryClient(server, &waits).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryTransport_CancelledWhileWaiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := &RetryTransport{Base: server.Client().Transport, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	client := &Client{HTTPClient: &http.Client{Transport: transport}, ForecastURL: server.URL}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := client.GetWeather(ctx, 0, 0)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryTransport_RetriesRefusedConnections(t *testing.T) {
+	// Reserve a port with nothing listening on it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var waits []time.Duration
+	transport := &RetryTransport{
+		MaxRetries: 2,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	}
+	_, err = (&http.Client{Transport: transport}).Get("http://" + addr)
+
+	assert.Error(t, err)
+	assert.Len(t, waits, 2)
+}
+
+func TestRetryTransport_AttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`ok`))
+	}))
+	defer server.Close()
+
+	transport := &RetryTransport{Base: server.Client().Transport, BaseDelay: time.Millisecond, AttemptTimeout: 50 * time.Millisecond}
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"Seconds", "3", 3 * time.Second, true},
+		{"HTTP date", "Sat, 01 Mar 2025 12:00:10 GMT", 10 * time.Second, true},
+		{"Date in the past", "Sat, 01 Mar 2025 11:00:00 GMT", 0, true},
+		{"Missing", "", 0, false},
+		{"Garbage", "soon", 0, false},
+		{"Negative", "-1", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := retryAfter(tt.value, now)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxReverseDistance is the farthest, in kilometers, a place may be from the
+// coordinates to be used as their label. The gazetteer only lists major
+// cities, so farther ones would give misleading labels.
+const MaxReverseDistance = 50.0
+
+// ReverseGeocoder finds the populated place nearest to coordinates.
+// The returned Location keeps the queried coordinates, takes its name, country
+// and timezone from the place, and records the distance to it.
+type ReverseGeocoder interface {
+	ReverseGeocode(ctx context.Context, lat, lon float64) (*Location, error)
+}
+
+var _ ReverseGeocoder = (*Client)(nil)
+
+// ReverseGeocodingResponse represents the response from a Nominatim-compatible reverse geocoding API
+type ReverseGeocodingResponse struct {
+	Latitude  string `json:"lat"`
+	Longitude string `json:"lon"`
+	Name      string `json:"name"`
+	Error     string `json:"error"`
+	Address   struct {
+		City         string `json:"city"`
+		Town         string `json:"town"`
+		Village      string `json:"village"`
+		Hamlet       string `json:"hamlet"`
+		Municipality string `json:"municipality"`
+		State        string `json:"state"`
+		County       string `json:"county"`
+		CountryCode  string `json:"country_code"`
+	} `json:"address"`
+}
+
+// ReverseGeocode labels coordinates with the nearest populated place.
+// Open-Meteo has no reverse geocoding endpoint, so the online lookup uses
+// ReverseGeocodingURL when it is set; without it, or when the lookup fails,
+// the embedded gazetteer answers offline.
+func (c *Client) ReverseGeocode(ctx context.Context, lat, lon float64) (*Location, error) {
+	if c.ReverseGeocodingURL != "" {
+		location, err := c.reverseGeocodeOnline(ctx, lat, lon)
+		if err == nil || ctx.Err() != nil {
+			return location, err
+		}
+	}
+	return EmbeddedGazetteer().ReverseGeocode(ctx, lat, lon)
+}
+
+// reverseGeocodeOnline queries a Nominatim-compatible /reverse endpoint
+func (c *Client) reverseGeocodeOnline(ctx context.Context, lat, lon float64) (*Location, error) {
+	query := url.Values{}
+	query.Set("lat", formatCoordinate(lat))
+	query.Set("lon", formatCoordinate(lon))
+	query.Set("format", "jsonv2")
+	query.Set("zoom", "10")
+	query.Set("accept-language", c.language())
+	apiURL := endpoint(c.ReverseGeocodingURL, "", "/reverse", query)
+
+	var revResp ReverseGeocodingResponse
+	if err := c.getJSON(ctx, apiURL, "place", &revResp); err != nil {
+		return nil, err
+	}
+	if revResp.Error != "" {
+		return nil, errors.New(revResp.Error)
+	}
+
+	a := revResp.Address
+	name := firstNonEmpty(a.City, a.Town, a.Village, a.Hamlet, a.Municipality, revResp.Name)
+	if name == "" {
+		return nil, fmt.Errorf("no populated place nearby")
+	}
+
+	location := &Location{
+		Name:      name,
+		Latitude:  lat,
+		Longitude: lon,
+		Country:   strings.ToUpper(a.CountryCode),
+		Admin1:    a.State,
+		Admin2:    a.County,
+	}
+
+	placeLat, errLat := strconv.ParseFloat(revResp.Latitude, 64)
+	placeLon, errLon := strconv.ParseFloat(revResp.Longitude, 64)
+	if errLat == nil && errLon == nil {
+		location.Distance = Distance(lat, lon, placeLat, placeLon)
+	}
+	return location, nil
+}
+
+// Gazetteer is an offline ReverseGeocoder backed by a list of populated places
+type Gazetteer struct {
+	places []Location
+}
+
+var _ ReverseGeocoder = (*Gazetteer)(nil)
+
+//go:embed gazetteer.csv
+var gazetteerCSV string
+
+var (
+	embeddedGazetteer     *Gazetteer
+	embeddedGazetteerOnce sync.Once
+)
+
+// EmbeddedGazetteer returns the gazetteer of major world cities compiled into sky
+func EmbeddedGazetteer() *Gazetteer {
+	embeddedGazetteerOnce.Do(func() {
+		g, err := ParseGazetteer(gazetteerCSV)
+		if err != nil {
+			panic("api: invalid embedded gazetteer: " + err.Error())
+		}
+		embeddedGazetteer = g
+	})
+	return embeddedGazetteer
+}
+
+// ParseGazetteer reads places from CSV lines of name,country_code,latitude,longitude,population.
+// Lines starting with # are comments.
+func ParseGazetteer(data string) (*Gazetteer, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = 5
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gazetteer{places: make([]Location, 0, len(records))}
+	for _, rec := range records {
+		lat, errLat := strconv.ParseFloat(rec[2], 64)
+		lon, errLon := strconv.ParseFloat(rec[3], 64)
+		population, errPop := strconv.Atoi(rec[4])
+		if err := errors.Join(errLat, errLon, errPop); err != nil {
+			return nil, fmt.Errorf("place %q: %w", rec[0], err)
+		}
+		g.places = append(g.places, Location{
+			Name:       rec[0],
+			Country:    rec[1],
+			Latitude:   lat,
+			Longitude:  lon,
+			Population: population,
+		})
+	}
+	return g, nil
+}
+
+// ReverseGeocode returns the gazetteer place nearest to the coordinates
+func (g *Gazetteer) ReverseGeocode(ctx context.Context, lat, lon float64) (*Location, error) {
+	var nearest *Location
+	best := math.Inf(1)
+	for i := range g.places {
+		if d := Distance(lat, lon, g.places[i].Latitude, g.places[i].Longitude); d < best {
+			nearest, best = &g.places[i], d
+		}
+	}
+
+	if nearest == nil || best > MaxReverseDistance {
+		return nil, fmt.Errorf("no populated place within %.0f km", MaxReverseDistance)
+	}
+
+	location := *nearest
+	location.Latitude = lat
+	location.Longitude = lon
+	location.Distance = best
+	return &location, nil
+}
+
+// Distance returns the great-circle distance in kilometers between two points
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
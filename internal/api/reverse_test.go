@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	// Tokyo to Osaka is roughly 400 km
+	assert.InDelta(t, 397, Distance(35.6895, 139.6917, 34.6937, 135.5023), 5)
+	// One degree of latitude is roughly 111 km
+	assert.InDelta(t, 111.2, Distance(0, 0, 1, 0), 0.1)
+	assert.Equal(t, 0.0, Distance(51.5, -0.12, 51.5, -0.12))
+	// Across the antimeridian
+	assert.InDelta(t, 22.2, Distance(0, 179.9, 0, -179.9), 0.1)
+}
+
+func TestEmbeddedGazetteer_ReverseGeocode(t *testing.T) {
+	g := EmbeddedGazetteer()
+
+	location, err := g.ReverseGeocode(context.Background(), 35.2412, 139.0786)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hakone", location.Name)
+	assert.Equal(t, "JP", location.Country)
+	assert.Equal(t, 35.2412, location.Latitude)
+	assert.Equal(t, 139.0786, location.Longitude)
+	assert.InDelta(t, 2.7, location.Distance, 0.5)
+}
+
+func TestEmbeddedGazetteer_MiddleOfOcean(t *testing.T) {
+	_, err := EmbeddedGazetteer().ReverseGeocode(context.Background(), -45, -120)
+
+	assert.Error(t, err)
+}
+
+func TestEmbeddedGazetteer_Countryside(t *testing.T) {
+	// Central Nevada is a few hundred kilometers from the nearest listed city
+	_, err := EmbeddedGazetteer().ReverseGeocode(context.Background(), 39.5, -116.5)
+
+	assert.ErrorContains(t, err, "no populated place within 50 km")
+}
+
+func TestParseGazetteer(t *testing.T) {
+	g, err := ParseGazetteer("# comment\nSpringfield,US,39.8017,-89.6437,114394\n\"Xi'an, Shaanxi\",CN,34.2583,108.9286,6501190\n")
+	require.NoError(t, err)
+	require.Len(t, g.places, 2)
+	assert.Equal(t, "Xi'an, Shaanxi", g.places[1].Name)
+	assert.Equal(t, 114394, g.places[0].Population)
+
+	_, err = ParseGazetteer("Nowhere,XX,north,0,0\n")
+	assert.Error(t, err)
+	_, err = ParseGazetteer("Nowhere,XX\n")
+	assert.Error(t, err)
+}
+
+func TestClient_ReverseGeocode_Online(t *testing.T) {
+	var gotPath, gotLat string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotLat = r.URL.Query().Get("lat")
+		w.Write([]byte(`{"lat":"35.2324","lon":"139.1069","name":"Hakone",
+			"address":{"town":"Hakone","county":"Ashigarashimo","state":"Kanagawa","country_code":"jp"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ReverseGeocodingURL: server.URL}
+	location, err := client.ReverseGeocode(context.Background(), 35.2412, 139.0786)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/reverse", gotPath)
+	assert.Equal(t, "35.2412", gotLat)
+	assert.Equal(t, "Hakone", location.Name)
+	assert.Equal(t, "JP", location.Country)
+	assert.Equal(t, "Kanagawa", location.Admin1)
+	assert.InDelta(t, 2.7, location.Distance, 0.5)
+}
+
+func TestClient_ReverseGeocode_FallsBackToGazetteer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		client *Client
+	}{
+		{"No online service", &Client{}},
+		{"Online service fails", &Client{HTTPClient: server.Client(), ReverseGeocodingURL: server.URL}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := tt.client.ReverseGeocode(context.Background(), 48.86, 2.35)
+			require.NoError(t, err)
+			assert.Equal(t, "Paris", location.Name)
+			assert.Equal(t, "FR", location.Country)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TemperatureUnit is the unit used for temperatures
+type TemperatureUnit string
+
+// WindSpeedUnit is the unit used for wind speeds
+type WindSpeedUnit string
+
+// PrecipitationUnit is the unit used for precipitation amounts
+type PrecipitationUnit string
+
+// DistanceUnit is the unit of distances reported by the API, such as visibility
+type DistanceUnit string
+
+const (
+	Celsius    TemperatureUnit = "celsius"
+	Fahrenheit TemperatureUnit = "fahrenheit"
+	// Kelvin is not supported by Open-Meteo and is converted from Celsius
+	Kelvin TemperatureUnit = "kelvin"
+
+	KilometersPerHour WindSpeedUnit = "kmh"
+	MilesPerHour      WindSpeedUnit = "mph"
+	MetersPerSecond   WindSpeedUnit = "ms"
+	Knots             WindSpeedUnit = "kn"
+
+	Millimeters PrecipitationUnit = "mm"
+	Inches      PrecipitationUnit = "inch"
+
+	// Open-Meteo reports visibility in feet when precipitation is in inches
+	Meters DistanceUnit = "m"
+	Feet   DistanceUnit = "ft"
+)
+
+// Units selects the unit of each measured quantity.
+// Empty fields fall back to the metric unit.
+type Units struct {
+	Temperature   TemperatureUnit
+	WindSpeed     WindSpeedUnit
+	Precipitation PrecipitationUnit
+}
+
+var (
+	// MetricUnits is the default unit system: °C, km/h and mm
+	MetricUnits = Units{Temperature: Celsius, WindSpeed: KilometersPerHour, Precipitation: Millimeters}
+
+	// ImperialUnits uses °F, mph and inches
+	ImperialUnits = Units{Temperature: Fahrenheit, WindSpeed: MilesPerHour, Precipitation: Inches}
+
+	// ScientificUnits uses SI units: K, m/s and mm
+	ScientificUnits = Units{Temperature: Kelvin, WindSpeed: MetersPerSecond, Precipitation: Millimeters}
+)
+
+// UnitSystem returns the units of a named unit system: metric, imperial or scientific
+func UnitSystem(name string) (Units, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "metric":
+		return MetricUnits, nil
+	case "imperial":
+		return ImperialUnits, nil
+	case "scientific", "si":
+		return ScientificUnits, nil
+	}
+	return Units{}, fmt.Errorf("unknown unit system %q (want metric, imperial or scientific)", name)
+}
+
+// ParseTemperatureUnit parses a temperature unit name or symbol
+func ParseTemperatureUnit(s string) (TemperatureUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "celsius", "c", "°c":
+		return Celsius, nil
+	case "fahrenheit", "f", "°f":
+		return Fahrenheit, nil
+	case "kelvin", "k":
+		return Kelvin, nil
+	}
+	return "", fmt.Errorf("unknown temperature unit %q (want celsius, fahrenheit or kelvin)", s)
+}
+
+// ParseWindSpeedUnit parses a wind speed unit name or symbol
+func ParseWindSpeedUnit(s string) (WindSpeedUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "kmh", "km/h", "kph":
+		return KilometersPerHour, nil
+	case "mph":
+		return MilesPerHour, nil
+	case "ms", "m/s":
+		return MetersPerSecond, nil
+	case "kn", "kt", "knots":
+		return Knots, nil
+	}
+	return "", fmt.Errorf("unknown wind speed unit %q (want km/h, mph, m/s or kn)", s)
+}
+
+// ParsePrecipitationUnit parses a precipitation unit name or symbol
+func ParsePrecipitationUnit(s string) (PrecipitationUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "mm":
+		return Millimeters, nil
+	case "inch", "in", "inches":
+		return Inches, nil
+	}
+	return "", fmt.Errorf("unknown precipitation unit %q (want mm or inch)", s)
+}
+
+// Symbol returns the label printed after temperature values
+func (u TemperatureUnit) Symbol() string {
+	switch u {
+	case Fahrenheit:
+		return "°F"
+	case Kelvin:
+		return "K"
+	default:
+		return "°C"
+	}
+}
+
+// Symbol returns the label printed after wind speed values
+func (u WindSpeedUnit) Symbol() string {
+	switch u {
+	case MilesPerHour:
+		return "mph"
+	case MetersPerSecond:
+		return "m/s"
+	case Knots:
+		return "kn"
+	default:
+		return "km/h"
+	}
+}
+
+// Symbol returns the label printed after precipitation values
+func (u PrecipitationUnit) Symbol() string {
+	switch u {
+	case Inches:
+		return "in"
+	default:
+		return "mm"
+	}
+}
+
+// Symbol returns the label printed after distance values
+func (u DistanceUnit) Symbol() string {
+	if u == Feet {
+		return "ft"
+	}
+	return "m"
+}
+
+// Meters converts a distance in u to meters
+func (u DistanceUnit) Meters(v float64) float64 {
+	if u == Feet {
+		return v * 0.3048
+	}
+	return v
+}
+
+// parseDistanceUnit parses a distance unit as labeled in API responses;
+// anything but feet is taken as meters
+func parseDistanceUnit(s string) DistanceUnit {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ft", "feet":
+		return Feet
+	}
+	return Meters
+}
+
+// setQuery adds the Open-Meteo unit parameters to a forecast request
+func (u Units) setQuery(query url.Values) {
+	temperature := Celsius
+	if u.Temperature == Fahrenheit {
+		temperature = Fahrenheit
+	}
+	query.Set("temperature_unit", string(temperature))
+
+	if u.WindSpeed != "" {
+		query.Set("wind_speed_unit", string(u.WindSpeed))
+	}
+	if u.Precipitation != "" {
+		query.Set("precipitation_unit", string(u.Precipitation))
+	}
+}
+
+// temperature converts a temperature returned by the API into the requested unit
+func (u Units) temperature(v float64) float64 {
+	if u.Temperature == Kelvin {
+		return v + 273.15
+	}
+	return v
+}
+
+// ConvertTemperature converts a temperature between units
+func ConvertTemperature(v float64, from, to TemperatureUnit) float64 {
+	celsius := v
+	switch from {
+	case Fahrenheit:
+		celsius = (v - 32) * 5 / 9
+	case Kelvin:
+		celsius = v - 273.15
+	}
+
+	switch to {
+	case Fahrenheit:
+		return celsius*9/5 + 32
+	case Kelvin:
+		return celsius + 273.15
+	default:
+		return celsius
+	}
+}
+
+// ConvertWindSpeed converts a wind speed between units
+func ConvertWindSpeed(v float64, from, to WindSpeedUnit) float64 {
+	// Meters per second per unit
+	factors := map[WindSpeedUnit]float64{
+		KilometersPerHour: 1000.0 / 3600,
+		MilesPerHour:      1609.344 / 3600,
+		MetersPerSecond:   1,
+		Knots:             1852.0 / 3600,
+	}
+	fromFactor, ok := factors[from]
+	if !ok {
+		fromFactor = factors[KilometersPerHour]
+	}
+	toFactor, ok := factors[to]
+	if !ok {
+		toFactor = factors[KilometersPerHour]
+	}
+	return v * fromFactor / toFactor
+}
+
+// ConvertPrecipitation converts a precipitation amount between units
+func ConvertPrecipitation(v float64, from, to PrecipitationUnit) float64 {
+	if from == Inches {
+		v *= 25.4
+	}
+	if to == Inches {
+		return v / 25.4
+	}
+	return v
+}
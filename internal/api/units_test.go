@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitSystem(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  Units
+		wantError bool
+	}{
+		{"metric", MetricUnits, false},
+		{"Imperial", ImperialUnits, false},
+		{" scientific ", ScientificUnits, false},
+		{"si", ScientificUnits, false},
+		{"nautical", Units{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units, err := UnitSystem(tt.name)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, units)
+		})
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	temperature, err := ParseTemperatureUnit("F")
+	require.NoError(t, err)
+	assert.Equal(t, Fahrenheit, temperature)
+
+	wind, err := ParseWindSpeedUnit("m/s")
+	require.NoError(t, err)
+	assert.Equal(t, MetersPerSecond, wind)
+
+	wind, err = ParseWindSpeedUnit("knots")
+	require.NoError(t, err)
+	assert.Equal(t, Knots, wind)
+
+	precipitation, err := ParsePrecipitationUnit("in")
+	require.NoError(t, err)
+	assert.Equal(t, Inches, precipitation)
+
+	_, err = ParseTemperatureUnit("rankine")
+	assert.Error(t, err)
+	_, err = ParseWindSpeedUnit("beaufort")
+	assert.Error(t, err)
+	_, err = ParsePrecipitationUnit("cm")
+	assert.Error(t, err)
+}
+
+func TestUnits_Symbols(t *testing.T) {
+	assert.Equal(t, "°C", Units{}.Temperature.Symbol())
+	assert.Equal(t, "km/h", Units{}.WindSpeed.Symbol())
+	assert.Equal(t, "mm", Units{}.Precipitation.Symbol())
+
+	assert.Equal(t, "°F", ImperialUnits.Temperature.Symbol())
+	assert.Equal(t, "mph", ImperialUnits.WindSpeed.Symbol())
+	assert.Equal(t, "in", ImperialUnits.Precipitation.Symbol())
+
+	assert.Equal(t, "K", ScientificUnits.Temperature.Symbol())
+	assert.Equal(t, "m/s", ScientificUnits.WindSpeed.Symbol())
+	assert.Equal(t, "kn", Knots.Symbol())
+}
+
+func TestUnits_SetQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		units  Units
+		expect map[string]string
+	}{
+		{"Zero value", Units{}, map[string]string{"temperature_unit": "celsius"}},
+		{"Imperial", ImperialUnits, map[string]string{"temperature_unit": "fahrenheit", "wind_speed_unit": "mph", "precipitation_unit": "inch"}},
+		{"Kelvin is requested as Celsius", ScientificUnits, map[string]string{"temperature_unit": "celsius", "wind_speed_unit": "ms", "precipitation_unit": "mm"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{}
+			tt.units.setQuery(query)
+
+			assert.Len(t, query, len(tt.expect))
+			for key, value := range tt.expect {
+				assert.Equal(t, value, query.Get(key))
+			}
+		})
+	}
+}
+
+func TestClient_GetWeather_Kelvin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "celsius", r.URL.Query().Get("temperature_unit"))
+		w.Write([]byte(`{"current":{"temperature_2m":15.0,"apparent_temperature":-5.0,"weather_code":0}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL, Units: ScientificUnits}
+	weather, err := client.GetWeather(context.Background(), 0, 0)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 288.15, weather.Temperature, 1e-9)
+	assert.InDelta(t, 268.15, weather.ApparentTemp, 1e-9)
+	assert.Equal(t, ScientificUnits, weather.Units)
+}
+
+func TestConvertTemperature(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to TemperatureUnit
+		expected float64
+	}{
+		{100, Celsius, Fahrenheit, 212},
+		{32, Fahrenheit, Celsius, 0},
+		{0, Celsius, Kelvin, 273.15},
+		{273.15, Kelvin, Fahrenheit, 32},
+		{21.5, Celsius, Celsius, 21.5},
+		{10, "", Fahrenheit, 50},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, ConvertTemperature(tt.value, tt.from, tt.to), 1e-9, "%v %s -> %s", tt.value, tt.from, tt.to)
+	}
+}
+
+func TestConvertWindSpeed(t *testing.T) {
+	assert.InDelta(t, 10.0, ConvertWindSpeed(36, KilometersPerHour, MetersPerSecond), 1e-9)
+	assert.InDelta(t, 62.137, ConvertWindSpeed(100, KilometersPerHour, MilesPerHour), 1e-3)
+	assert.InDelta(t, 1.852, ConvertWindSpeed(1, Knots, ""), 1e-9)
+	assert.InDelta(t, 5.0, ConvertWindSpeed(5, MilesPerHour, MilesPerHour), 1e-9)
+}
+
+func TestConvertPrecipitation(t *testing.T) {
+	assert.InDelta(t, 25.4, ConvertPrecipitation(1, Inches, Millimeters), 1e-9)
+	assert.InDelta(t, 0.5, ConvertPrecipitation(12.7, Millimeters, Inches), 1e-9)
+	assert.InDelta(t, 3.0, ConvertPrecipitation(3, "", ""), 1e-9)
+}
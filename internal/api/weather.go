@@ -2,19 +2,42 @@ package api
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
+// currentVariables are the current conditions requested from the Weather API
+const currentVariables = "temperature_2m,apparent_temperature,weather_code,relative_humidity_2m,dew_point_2m," +
+	"wind_speed_10m,wind_direction_10m,wind_gusts_10m,surface_pressure,precipitation,cloud_cover,uv_index,visibility,is_day"
+
 // WeatherResponse represents the response from Open-Meteo Weather API
 type WeatherResponse struct {
-	Current struct {
-		Temperature     float64 `json:"temperature_2m"`
-		ApparentTemp    float64 `json:"apparent_temperature"`
-		WeatherCode     int     `json:"weather_code"`
-	} `json:"current"`
+	Current      CurrentWeather `json:"current"`
+	CurrentUnits CurrentUnits   `json:"current_units"`
+}
+
+// CurrentUnits represents the units of the current block, as labeled by the API
+type CurrentUnits struct {
+	Visibility string `json:"visibility"`
+}
+
+// CurrentWeather represents the current block of the Open-Meteo Weather API response
+type CurrentWeather struct {
+	Temperature   float64 `json:"temperature_2m"`
+	ApparentTemp  float64 `json:"apparent_temperature"`
+	WeatherCode   int     `json:"weather_code"`
+	Humidity      int     `json:"relative_humidity_2m"`
+	DewPoint      float64 `json:"dew_point_2m"`
+	WindSpeed     float64 `json:"wind_speed_10m"`
+	WindDirection int     `json:"wind_direction_10m"`
+	WindGusts     float64 `json:"wind_gusts_10m"`
+	Pressure      float64 `json:"surface_pressure"`
+	Precipitation float64 `json:"precipitation"`
+	CloudCover    int     `json:"cloud_cover"`
+	UVIndex       float64 `json:"uv_index"`
+	Visibility    float64 `json:"visibility"`
+	IsDay         int     `json:"is_day"`
 }
 
 // Weather represents current weather conditions
@@ -23,6 +46,29 @@ type Weather struct {
 	ApparentTemp    float64
 	WeatherCode     int
 	WeatherCodeDesc string
+
+	Humidity      int     // relative humidity, %
+	DewPoint      float64 // in the temperature unit
+	WindSpeed     float64 // in the wind speed unit
+	WindDirection int     // degrees, direction the wind blows from
+	WindGusts     float64 // in the wind speed unit
+	Pressure      float64 // surface pressure, hPa
+	Precipitation float64 // in the precipitation unit
+	CloudCover    int     // %
+	UVIndex       float64
+	Visibility    float64 // in VisibilityUnit
+	IsDay         bool
+
+	// VisibilityUnit is the unit the API reported visibility in; empty means meters
+	VisibilityUnit DistanceUnit
+
+	Units Units
+
+	// FetchedAt is when the conditions were retrieved from the API
+	FetchedAt time.Time
+
+	// Stale marks cached conditions served because fresh ones were unavailable
+	Stale bool
 }
 
 // WeatherCodeDescription returns a human-readable description and emoji for weather codes
@@ -61,6 +107,40 @@ func WeatherCodeDescription(code int) string {
 	return "Unknown"
 }
 
+// WeatherGroup is a broad category of weather codes
+type WeatherGroup string
+
+const (
+	GroupClear   WeatherGroup = "clear"
+	GroupCloudy  WeatherGroup = "cloudy"
+	GroupFog     WeatherGroup = "fog"
+	GroupRain    WeatherGroup = "rain"
+	GroupSnow    WeatherGroup = "snow"
+	GroupThunder WeatherGroup = "thunder"
+	GroupUnknown WeatherGroup = "unknown"
+)
+
+// WeatherCodeGroup returns the category of a WMO weather code.
+// Drizzle, freezing rain and showers count as rain.
+func WeatherCodeGroup(code int) WeatherGroup {
+	switch {
+	case code == 0 || code == 1:
+		return GroupClear
+	case code == 2 || code == 3:
+		return GroupCloudy
+	case code == 45 || code == 48:
+		return GroupFog
+	case code >= 51 && code <= 67, code >= 80 && code <= 82:
+		return GroupRain
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return GroupSnow
+	case code >= 95 && code <= 99:
+		return GroupThunder
+	default:
+		return GroupUnknown
+	}
+}
+
 // WeatherCodeEmoji returns an emoji for a given weather code
 func WeatherCodeEmoji(code int) string {
 	emojis := map[int]string{
@@ -97,38 +177,55 @@ func WeatherCodeEmoji(code int) string {
 }
 
 // GetWeather retrieves current weather for a given location
-func GetWeather(ctx context.Context, lat, lon float64) (*Weather, error) {
-	apiURL := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&current=temperature_2m,apparent_temperature,weather_code&temperature_unit=celsius&timezone=auto", lat, lon)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+func (c *Client) GetWeather(ctx context.Context, lat, lon float64) (*Weather, error) {
+	query := url.Values{}
+	query.Set("latitude", formatCoordinate(lat))
+	query.Set("longitude", formatCoordinate(lon))
+	query.Set("current", currentVariables)
+	c.Units.setQuery(query)
+	query.Set("timezone", "auto")
+	apiURL := endpoint(c.ForecastURL, DefaultForecastURL, "/v1/forecast", query)
 
-	resp, err := DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch weather: %w", err)
+	var weatherResp WeatherResponse
+	if err := c.getJSON(ctx, apiURL, "weather", &weatherResp); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
+	weather := weatherResp.weather(c.Units)
+	weather.FetchedAt = time.Now()
+	return weather, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+// weather converts the response into Weather
+func (r *WeatherResponse) weather(units Units) *Weather {
+	weather := r.Current.weather(units)
+	weather.VisibilityUnit = parseDistanceUnit(r.CurrentUnits.Visibility)
+	return weather
+}
 
-	var weatherResp WeatherResponse
-	if err := json.Unmarshal(body, &weatherResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// weather converts the current block of the response into Weather
+func (cur *CurrentWeather) weather(units Units) *Weather {
+	return &Weather{
+		Temperature:     units.temperature(cur.Temperature),
+		ApparentTemp:    units.temperature(cur.ApparentTemp),
+		WeatherCode:     cur.WeatherCode,
+		WeatherCodeDesc: WeatherCodeDescription(cur.WeatherCode),
+		Humidity:        cur.Humidity,
+		DewPoint:        units.temperature(cur.DewPoint),
+		WindSpeed:       cur.WindSpeed,
+		WindDirection:   cur.WindDirection,
+		WindGusts:       cur.WindGusts,
+		Pressure:        cur.Pressure,
+		Precipitation:   cur.Precipitation,
+		CloudCover:      cur.CloudCover,
+		UVIndex:         cur.UVIndex,
+		Visibility:      cur.Visibility,
+		IsDay:           cur.IsDay == 1,
+		Units:           units,
 	}
+}
 
-	return &Weather{
-		Temperature:     weatherResp.Current.Temperature,
-		ApparentTemp:    weatherResp.Current.ApparentTemp,
-		WeatherCode:     weatherResp.Current.WeatherCode,
-		WeatherCodeDesc: WeatherCodeDescription(weatherResp.Current.WeatherCode),
-	}, nil
+// formatCoordinate formats a latitude or longitude for request parameters
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -130,11 +131,7 @@ func TestWeatherCodeEmoji_UnknownCodes(t *testing.T) {
 func TestGetWeather_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := WeatherResponse{
-			Current: struct {
-				Temperature     float64 `json:"temperature_2m"`
-				ApparentTemp    float64 `json:"apparent_temperature"`
-				WeatherCode     int     `json:"weather_code"`
-			}{
+			Current: CurrentWeather{
 				Temperature:  15.5,
 				ApparentTemp: 14.2,
 				WeatherCode:  0,
@@ -253,3 +250,130 @@ func TestWeatherCodeEmoji_AllCodesReturnEmoji(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetWeather(t *testing.T) {
+	var gotQuery map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/forecast", r.URL.Path)
+		gotQuery = map[string]string{}
+		for key := range r.URL.Query() {
+			gotQuery[key] = r.URL.Query().Get(key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"current":{"temperature_2m":15.5,"apparent_temperature":14.2,"weather_code":3}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL + "/"}
+	weather, err := client.GetWeather(context.Background(), 35.6762, 139.6503)
+	require.NoError(t, err)
+
+	assert.Equal(t, "35.6762", gotQuery["latitude"])
+	assert.Equal(t, "139.6503", gotQuery["longitude"])
+	assert.Equal(t, "auto", gotQuery["timezone"])
+	assert.Equal(t, 15.5, weather.Temperature)
+	assert.Equal(t, 14.2, weather.ApparentTemp)
+	assert.Equal(t, 3, weather.WeatherCode)
+	assert.Equal(t, "Overcast", weather.WeatherCodeDesc)
+}
+
+func TestClient_GetWeather_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	_, err := client.GetWeather(context.Background(), 0, 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API returned status 503")
+}
+
+func TestClient_GetWeather_Details(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("current"), "relative_humidity_2m")
+		assert.Contains(t, r.URL.Query().Get("current"), "is_day")
+		w.Write([]byte(`{"current":{"temperature_2m":21.0,"apparent_temperature":20.5,"weather_code":1,
+			"relative_humidity_2m":64,"dew_point_2m":13.9,"wind_speed_10m":14.2,"wind_direction_10m":225,
+			"wind_gusts_10m":31.7,"surface_pressure":1008.4,"precipitation":0.2,"cloud_cover":35,
+			"uv_index":5.35,"visibility":24140.0,"is_day":1}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL}
+	weather, err := client.GetWeather(context.Background(), 0, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, 64, weather.Humidity)
+	assert.Equal(t, 13.9, weather.DewPoint)
+	assert.Equal(t, 14.2, weather.WindSpeed)
+	assert.Equal(t, 225, weather.WindDirection)
+	assert.Equal(t, 31.7, weather.WindGusts)
+	assert.Equal(t, 1008.4, weather.Pressure)
+	assert.Equal(t, 0.2, weather.Precipitation)
+	assert.Equal(t, 35, weather.CloudCover)
+	assert.Equal(t, 5.35, weather.UVIndex)
+	assert.Equal(t, 24140.0, weather.Visibility)
+	assert.True(t, weather.IsDay)
+}
+
+func TestClient_GetWeather_VisibilityUnit(t *testing.T) {
+	tests := []struct {
+		name  string
+		units Units
+		body  string
+		want  DistanceUnit
+	}{
+		{"Metric", MetricUnits, `{"current_units":{"visibility":"m"},"current":{"visibility":24140.0}}`, Meters},
+		{"Imperial", ImperialUnits, `{"current_units":{"visibility":"ft"},"current":{"visibility":79200.0}}`, Feet},
+		{"Unlabeled", MetricUnits, `{"current":{"visibility":24140.0}}`, Meters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := &Client{HTTPClient: server.Client(), ForecastURL: server.URL, Units: tt.units}
+			weather, err := client.GetWeather(context.Background(), 0, 0)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, weather.VisibilityUnit)
+			assert.InDelta(t, 24140.0, weather.VisibilityUnit.Meters(weather.Visibility), 1)
+		})
+	}
+}
+
+func TestCurrentWeather_Night(t *testing.T) {
+	var resp WeatherResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"current":{"temperature_2m":2.0,"dew_point_2m":-1.0,"is_day":0}}`), &resp))
+
+	weather := resp.Current.weather(ScientificUnits)
+
+	assert.False(t, weather.IsDay)
+	assert.InDelta(t, 272.15, weather.DewPoint, 1e-9)
+}
+
+func TestWeatherCodeGroup(t *testing.T) {
+	tests := []struct {
+		codes []int
+		group WeatherGroup
+	}{
+		{[]int{0, 1}, GroupClear},
+		{[]int{2, 3}, GroupCloudy},
+		{[]int{45, 48}, GroupFog},
+		{[]int{51, 55, 57, 61, 65, 67, 80, 82}, GroupRain},
+		{[]int{71, 75, 77, 85, 86}, GroupSnow},
+		{[]int{95, 96, 99}, GroupThunder},
+		{[]int{-1, 4, 100}, GroupUnknown},
+	}
+
+	for _, tt := range tests {
+		for _, code := range tt.codes {
+			assert.Equal(t, tt.group, WeatherCodeGroup(code), "code %d", code)
+		}
+	}
+}
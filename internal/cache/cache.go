@@ -0,0 +1,106 @@
+// Package cache keeps API responses on disk so repeated invocations can
+// skip the network and fall back to the last good data when it is down.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/fsutil"
+)
+
+// ErrMiss is returned when offline and the cache holds no data for a request
+var ErrMiss = errors.New("no cached data")
+
+// Dir returns the sky cache directory: $XDG_CACHE_HOME/sky, or the
+// platform cache directory when XDG_CACHE_HOME is unset
+func Dir(getenv func(string) string) (string, error) {
+	if dir := getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "sky"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+	return filepath.Join(dir, "sky"), nil
+}
+
+// Store saves JSON values in files under Dir, one file per key
+type Store struct {
+	Dir string
+
+	// now returns the current time; replaced in tests
+	now func() time.Time
+}
+
+// entry is the file format of a stored value
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// NewStore returns a Store keeping its files in dir
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Get decodes the value stored under key into v and returns when it was stored.
+// ok is false when there is no readable value for key.
+func (s *Store) Get(key string, v any) (storedAt time.Time, ok bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return time.Time{}, false
+	}
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		return time.Time{}, false
+	}
+	return e.StoredAt, true
+}
+
+// Put stores v under key, replacing any previous value
+func (s *Store) Put(key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry{Key: key, StoredAt: s.clock(), Value: value})
+	if err != nil {
+		return err
+	}
+
+	// Concurrent readers never see a partial entry
+	if err := fsutil.WriteFileAtomic(s.path(key), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Age returns how long ago storedAt was
+func (s *Store) Age(storedAt time.Time) time.Duration {
+	return s.clock().Sub(storedAt)
+}
+
+// path returns the file holding key; keys are hashed to stay filesystem-safe
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (s *Store) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
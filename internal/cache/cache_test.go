@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	env := map[string]string{"XDG_CACHE_HOME": "/tmp/xdg"}
+
+	dir, err := Dir(func(key string) string { return env[key] })
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/xdg", "sky"), dir)
+}
+
+func TestStore_PutGet(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &Store{Dir: filepath.Join(t.TempDir(), "sky"), now: func() time.Time { return now }}
+
+	require.NoError(t, store.Put("greeting", map[string]string{"hello": "world"}))
+
+	var got map[string]string
+	storedAt, ok := store.Get("greeting", &got)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"hello": "world"}, got)
+	assert.True(t, now.Equal(storedAt))
+
+	now = now.Add(5 * time.Minute)
+	assert.Equal(t, 5*time.Minute, store.Age(storedAt))
+}
+
+func TestStore_Miss(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	var got string
+	_, ok := store.Get("missing", &got)
+
+	assert.False(t, ok)
+}
+
+func TestStore_CorruptEntry(t *testing.T) {
+	store := NewStore(t.TempDir())
+	require.NoError(t, os.WriteFile(store.path("broken"), []byte("{not json"), 0o644))
+
+	var got string
+	_, ok := store.Get("broken", &got)
+
+	assert.False(t, ok)
+}
+
+func TestStore_Overwrite(t *testing.T) {
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Put("n", 1))
+	require.NoError(t, store.Put("n", 2))
+
+	var got int
+	_, ok := store.Get("n", &got)
+
+	require.True(t, ok)
+	assert.Equal(t, 2, got)
+
+	// No temporary files are left behind
+	files, err := os.ReadDir(store.Dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
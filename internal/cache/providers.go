@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+const (
+	// DefaultLocationTTL is how long geocoding results stay fresh; places rarely move
+	DefaultLocationTTL = 30 * 24 * time.Hour
+
+	// DefaultWeatherTTL is how long current conditions stay fresh
+	DefaultWeatherTTL = 10 * time.Minute
+)
+
+// Geocoder caches the locations resolved by Next
+type Geocoder struct {
+	Next  api.Geocoder
+	Store *Store
+
+	// TTL is how long a cached location is used without asking Next
+	TTL time.Duration
+
+	// Offline answers from the cache only, whatever its age
+	Offline bool
+
+	// Language is the language Next names places in; English when empty
+	Language string
+}
+
+var _ api.Geocoder = (*Geocoder)(nil)
+
+// GetLocation returns the cached location for city while it is fresh,
+// and otherwise asks Next, falling back to the stale entry when Next fails
+func (g *Geocoder) GetLocation(ctx context.Context, city string) (*api.Location, error) {
+	key := "location:" + strings.ToLower(strings.TrimSpace(city))
+	if g.Language != "" && g.Language != "en" {
+		key += ":" + g.Language
+	}
+
+	var cached api.Location
+	storedAt, ok := g.Store.Get(key, &cached)
+	if ok && (g.Offline || g.Store.Age(storedAt) < g.TTL) {
+		return &cached, nil
+	}
+	if g.Offline {
+		return nil, fmt.Errorf("%w for %q (offline)", ErrMiss, city)
+	}
+
+	location, err := g.Next.GetLocation(ctx, city)
+	if err != nil {
+		if ok && fallback(err) {
+			return &cached, nil
+		}
+		return nil, err
+	}
+
+	// A cache that cannot be written only costs a later request
+	_ = g.Store.Put(key, location)
+	return location, nil
+}
+
+// Weather caches the current conditions returned by Next.
+// Entries are keyed by coordinates rounded to about a kilometer and by Units.
+type Weather struct {
+	Next  api.WeatherProvider
+	Store *Store
+
+	// Units must match the units Next returns
+	Units api.Units
+
+	// TTL is how long cached conditions are used without asking Next
+	TTL time.Duration
+
+	// Offline answers from the cache only, whatever its age
+	Offline bool
+}
+
+var (
+	_ api.WeatherProvider      = (*Weather)(nil)
+	_ api.BatchWeatherProvider = (*Weather)(nil)
+)
+
+// GetWeather returns the cached conditions while they are fresh, and
+// otherwise asks Next, falling back to stale conditions when Next fails.
+// Stale conditions are marked with Weather.Stale.
+func (w *Weather) GetWeather(ctx context.Context, lat, lon float64) (*api.Weather, error) {
+	key := w.key(lat, lon)
+	cached, fresh := w.lookup(key)
+	if fresh {
+		return cached, nil
+	}
+	if w.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%w for %.4f, %.4f (offline)", ErrMiss, lat, lon)
+		}
+		cached.Stale = true
+		return cached, nil
+	}
+
+	weather, err := w.Next.GetWeather(ctx, lat, lon)
+	if err != nil {
+		if cached != nil && fallback(err) {
+			cached.Stale = true
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	_ = w.Store.Put(key, weather)
+	return weather, nil
+}
+
+// GetWeatherBatch returns the fresh cached conditions of points and asks
+// Next for the others in a single batch when Next supports batching.
+// A failed batch fails as a whole, without stale fallbacks; GetWeather
+// answers the points one by one.
+func (w *Weather) GetWeatherBatch(ctx context.Context, points []api.Coordinates) ([]*api.Weather, error) {
+	weathers := make([]*api.Weather, len(points))
+	var missed []int
+	for i, p := range points {
+		if cached, fresh := w.lookup(w.key(p.Latitude, p.Longitude)); fresh {
+			weathers[i] = cached
+		} else {
+			missed = append(missed, i)
+		}
+	}
+	if len(missed) == 0 {
+		return weathers, nil
+	}
+
+	batch, ok := w.Next.(api.BatchWeatherProvider)
+	if !ok || w.Offline {
+		for _, i := range missed {
+			weather, err := w.GetWeather(ctx, points[i].Latitude, points[i].Longitude)
+			if err != nil {
+				return nil, err
+			}
+			weathers[i] = weather
+		}
+		return weathers, nil
+	}
+
+	misses := make([]api.Coordinates, len(missed))
+	for j, i := range missed {
+		misses[j] = points[i]
+	}
+	results, err := batch.GetWeatherBatch(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range missed {
+		weathers[i] = results[j]
+		_ = w.Store.Put(w.key(points[i].Latitude, points[i].Longitude), results[j])
+	}
+	return weathers, nil
+}
+
+// key returns the cache key of the conditions at lat, lon in Units
+func (w *Weather) key(lat, lon float64) string {
+	return fmt.Sprintf("weather:%.2f,%.2f:%s/%s/%s",
+		lat, lon, w.Units.Temperature, w.Units.WindSpeed, w.Units.Precipitation)
+}
+
+// lookup returns the cached conditions stored under key, or nil, and
+// whether they are fresh enough to use without asking Next
+func (w *Weather) lookup(key string) (*api.Weather, bool) {
+	var cached api.Weather
+	storedAt, ok := w.Store.Get(key, &cached)
+	if !ok {
+		return nil, false
+	}
+	if cached.FetchedAt.IsZero() {
+		cached.FetchedAt = storedAt
+	}
+	return &cached, !w.Offline && w.Store.Age(storedAt) < w.TTL
+}
+
+// fallback reports whether a failed request may be answered with stale data.
+// Answers from the service, such as an unknown place, are not overridden,
+// nor is a cancellation by the user.
+func fallback(err error) bool {
+	return !errors.Is(err, api.ErrLocationNotFound) && !errors.Is(err, context.Canceled)
+}
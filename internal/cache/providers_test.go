@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+type fakeGeocoder struct {
+	location *api.Location
+	err      error
+	calls    int
+}
+
+func (f *fakeGeocoder) GetLocation(ctx context.Context, city string) (*api.Location, error) {
+	f.calls++
+	return f.location, f.err
+}
+
+type fakeWeather struct {
+	weather *api.Weather
+	err     error
+	calls   int
+
+	// batched are the points of each batch request
+	batched [][]api.Coordinates
+}
+
+func (f *fakeWeather) GetWeather(ctx context.Context, lat, lon float64) (*api.Weather, error) {
+	f.calls++
+	return f.weather, f.err
+}
+
+func (f *fakeWeather) GetWeatherBatch(ctx context.Context, points []api.Coordinates) ([]*api.Weather, error) {
+	f.batched = append(f.batched, points)
+	if f.err != nil {
+		return nil, f.err
+	}
+	weathers := make([]*api.Weather, len(points))
+	for i, p := range points {
+		weathers[i] = &api.Weather{Temperature: p.Latitude}
+	}
+	return weathers, nil
+}
+
+// newTestStore returns a store whose clock is advanced through the returned pointer
+func newTestStore(t *testing.T) (*Store, *time.Time) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &Store{Dir: t.TempDir(), now: func() time.Time { return now }}, &now
+}
+
+func TestGeocoder_CachesLocations(t *testing.T) {
+	store, now := newTestStore(t)
+	next := &fakeGeocoder{location: &api.Location{Name: "Berlin", Country: "DE", Latitude: 52.52, Longitude: 13.41}}
+	g := &Geocoder{Next: next, Store: store, TTL: DefaultLocationTTL}
+
+	first, err := g.GetLocation(context.Background(), "Berlin")
+	require.NoError(t, err)
+	*now = now.Add(24 * time.Hour)
+	second, err := g.GetLocation(context.Background(), " berlin ")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, next.calls)
+	assert.Equal(t, first, second)
+}
+
+func TestGeocoder_KeyedByLanguage(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeGeocoder{location: &api.Location{Name: "Munich", Country: "DE"}}
+	english := &Geocoder{Next: next, Store: store, TTL: DefaultLocationTTL}
+	german := &Geocoder{Next: next, Store: store, TTL: DefaultLocationTTL, Language: "de"}
+
+	_, err := english.GetLocation(context.Background(), "Munich")
+	require.NoError(t, err)
+	next.location = &api.Location{Name: "München", Country: "DE"}
+	location, err := german.GetLocation(context.Background(), "Munich")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, next.calls)
+	assert.Equal(t, "München", location.Name)
+}
+
+func TestGeocoder_Expired(t *testing.T) {
+	store, now := newTestStore(t)
+	next := &fakeGeocoder{location: &api.Location{Name: "Berlin"}}
+	g := &Geocoder{Next: next, Store: store, TTL: time.Hour}
+
+	_, err := g.GetLocation(context.Background(), "Berlin")
+	require.NoError(t, err)
+	*now = now.Add(2 * time.Hour)
+	_, err = g.GetLocation(context.Background(), "Berlin")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestGeocoder_NotFoundIsNotMasked(t *testing.T) {
+	store, now := newTestStore(t)
+	next := &fakeGeocoder{location: &api.Location{Name: "Berlin"}}
+	g := &Geocoder{Next: next, Store: store, TTL: time.Hour}
+	_, err := g.GetLocation(context.Background(), "Berlin")
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Hour)
+	next.location, next.err = nil, api.ErrLocationNotFound
+	_, err = g.GetLocation(context.Background(), "Berlin")
+
+	assert.ErrorIs(t, err, api.ErrLocationNotFound)
+}
+
+func TestGeocoder_Offline(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeGeocoder{location: &api.Location{Name: "Berlin"}}
+	g := &Geocoder{Next: next, Store: store, TTL: time.Hour, Offline: true}
+
+	_, err := g.GetLocation(context.Background(), "Berlin")
+
+	assert.ErrorIs(t, err, ErrMiss)
+	assert.Equal(t, 0, next.calls)
+}
+
+func TestWeather_FreshAndStale(t *testing.T) {
+	store, now := newTestStore(t)
+	fetchedAt := *now
+	next := &fakeWeather{weather: &api.Weather{Temperature: 18.5, Units: api.MetricUnits, FetchedAt: fetchedAt}}
+	w := &Weather{Next: next, Store: store, Units: api.MetricUnits, TTL: 10 * time.Minute}
+
+	_, err := w.GetWeather(context.Background(), 52.5200, 13.4050)
+	require.NoError(t, err)
+
+	// Nearby coordinates share the entry while it is fresh
+	*now = now.Add(5 * time.Minute)
+	fresh, err := w.GetWeather(context.Background(), 52.5201, 13.4049)
+	require.NoError(t, err)
+	assert.Equal(t, 1, next.calls)
+	assert.Equal(t, 18.5, fresh.Temperature)
+	assert.False(t, fresh.Stale)
+
+	// Once expired a failing fetch falls back to the last good data
+	*now = now.Add(time.Hour)
+	next.weather, next.err = nil, &api.NetworkError{What: "weather", Err: errors.New("connection refused")}
+	stale, err := w.GetWeather(context.Background(), 52.52, 13.405)
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.calls)
+	assert.Equal(t, 18.5, stale.Temperature)
+	assert.True(t, stale.Stale)
+	assert.True(t, fetchedAt.Equal(stale.FetchedAt))
+}
+
+func TestWeather_ErrorWithoutCache(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeWeather{err: &api.APIError{StatusCode: 503}}
+	w := &Weather{Next: next, Store: store, TTL: time.Minute}
+
+	_, err := w.GetWeather(context.Background(), 0, 0)
+
+	var apiErr *api.APIError
+	assert.ErrorAs(t, err, &apiErr)
+}
+
+func TestWeather_KeyedByUnits(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeWeather{weather: &api.Weather{Temperature: 18.5}}
+	metric := &Weather{Next: next, Store: store, Units: api.MetricUnits, TTL: time.Hour}
+	imperial := &Weather{Next: next, Store: store, Units: api.ImperialUnits, TTL: time.Hour}
+
+	_, err := metric.GetWeather(context.Background(), 1, 2)
+	require.NoError(t, err)
+	_, err = imperial.GetWeather(context.Background(), 1, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestWeather_Offline(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeWeather{weather: &api.Weather{Temperature: 18.5}}
+	online := &Weather{Next: next, Store: store, TTL: time.Hour}
+	offline := &Weather{Next: next, Store: store, TTL: time.Hour, Offline: true}
+
+	_, err := offline.GetWeather(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, ErrMiss)
+
+	_, err = online.GetWeather(context.Background(), 1, 2)
+	require.NoError(t, err)
+	weather, err := offline.GetWeather(context.Background(), 1, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, next.calls)
+	assert.True(t, weather.Stale)
+	assert.False(t, weather.FetchedAt.IsZero())
+}
+
+func TestWeather_Batch(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeWeather{weather: &api.Weather{Temperature: 18.5}}
+	w := &Weather{Next: next, Store: store, TTL: time.Hour}
+	_, err := w.GetWeather(context.Background(), 2, 2)
+	require.NoError(t, err)
+	points := []api.Coordinates{{Latitude: 1, Longitude: 1}, {Latitude: 2, Longitude: 2}, {Latitude: 3, Longitude: 3}}
+
+	weathers, err := w.GetWeatherBatch(context.Background(), points)
+	require.NoError(t, err)
+
+	// Only the points missing from the cache are batched
+	assert.Equal(t, [][]api.Coordinates{{points[0], points[2]}}, next.batched)
+	require.Len(t, weathers, 3)
+	assert.Equal(t, []float64{1, 18.5, 3}, []float64{weathers[0].Temperature, weathers[1].Temperature, weathers[2].Temperature})
+
+	// The batched conditions are cached too
+	_, err = w.GetWeatherBatch(context.Background(), points)
+	require.NoError(t, err)
+	assert.Len(t, next.batched, 1)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestWeather_BatchFailure(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeWeather{err: &api.APIError{StatusCode: 400}}
+	w := &Weather{Next: next, Store: store, TTL: time.Hour}
+
+	_, err := w.GetWeatherBatch(context.Background(), []api.Coordinates{{Latitude: 1}, {Latitude: 2}})
+
+	var apiErr *api.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Len(t, next.batched, 1)
+}
+
+func TestWeather_BatchWithoutBatchingNext(t *testing.T) {
+	store, _ := newTestStore(t)
+	next := &fakeWeather{weather: &api.Weather{Temperature: 18.5}}
+	w := &Weather{Next: struct{ api.WeatherProvider }{next}, Store: store, TTL: time.Hour}
+
+	weathers, err := w.GetWeatherBatch(context.Background(), []api.Coordinates{{Latitude: 1}, {Latitude: 2}})
+
+	// Next is asked point by point
+	require.NoError(t, err)
+	assert.Len(t, weathers, 2)
+	assert.Equal(t, 2, next.calls)
+	assert.Empty(t, next.batched)
+}
@@ -0,0 +1,296 @@
+// Package config reads and writes the sky configuration file, a small subset
+// of TOML: top-level "key = value" settings followed by [profile.NAME] tables
+// whose settings override them.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/fsutil"
+)
+
+// profilePrefix starts the table headers of named profiles
+const profilePrefix = "profile."
+
+// Dir returns the sky configuration directory: $XDG_CONFIG_HOME/sky, or the
+// platform configuration directory when XDG_CONFIG_HOME is unset
+func Dir(getenv func(string) string) (string, error) {
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "sky"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "sky"), nil
+}
+
+// Path returns the location of the configuration file, config.toml in Dir
+func Path(getenv func(string) string) (string, error) {
+	dir, err := Dir(getenv)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// File is a parsed configuration file. Its lines are kept so that Set
+// preserves comments and layout.
+type File struct {
+	lines []line
+}
+
+// line is one line of the file
+type line struct {
+	text string
+
+	// profile is the profile the line belongs to; "" before the first table
+	profile string
+
+	// key and value are set for settings
+	key, value string
+}
+
+// Load reads the configuration file at path.
+// A missing file is an empty configuration.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	f, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse parses the text of a configuration file
+func Parse(text string) (*File, error) {
+	f := &File{}
+	if text == "" {
+		return f, nil
+	}
+	profile := ""
+	for i, raw := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		l := line{text: strings.TrimRight(raw, "\r"), profile: profile}
+		content := strings.TrimSpace(l.text)
+
+		switch {
+		case content == "" || strings.HasPrefix(content, "#"):
+		case strings.HasPrefix(content, "["):
+			name, err := parseHeader(content)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			profile = name
+			l.profile = name
+		default:
+			key, value, err := parseSetting(content)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			l.key, l.value = key, value
+		}
+		f.lines = append(f.lines, l)
+	}
+	return f, nil
+}
+
+// parseHeader returns the profile name of a [profile.NAME] table header
+func parseHeader(content string) (string, error) {
+	header, _, _ := strings.Cut(content, "#")
+	header = strings.TrimSpace(header)
+	if !strings.HasSuffix(header, "]") {
+		return "", fmt.Errorf("unterminated table header %s", content)
+	}
+
+	name := strings.TrimSpace(header[1 : len(header)-1])
+	if !strings.HasPrefix(name, profilePrefix) || !validName(strings.TrimPrefix(name, profilePrefix)) {
+		return "", fmt.Errorf("unsupported table [%s] (want [profile.NAME])", name)
+	}
+	return strings.TrimPrefix(name, profilePrefix), nil
+}
+
+// parseSetting parses a key = value line. Strings may use double or single
+// quotes; bare values such as numbers and booleans are kept as written.
+func parseSetting(content string) (key, value string, err error) {
+	key, rest, ok := strings.Cut(content, "=")
+	key = strings.TrimSpace(key)
+	if !ok || !validName(key) {
+		return "", "", fmt.Errorf("expected key = value, got %q", content)
+	}
+
+	rest = strings.TrimSpace(rest)
+	var tail string
+	switch {
+	case strings.HasPrefix(rest, `"`):
+		end := closingQuote(rest)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string for %s", key)
+		}
+		if value, err = strconv.Unquote(rest[:end+1]); err != nil {
+			return "", "", fmt.Errorf("invalid string for %s: %w", key, err)
+		}
+		tail = rest[end+1:]
+	case strings.HasPrefix(rest, "'"):
+		end := strings.Index(rest[1:], "'")
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string for %s", key)
+		}
+		value, tail = rest[1:end+1], rest[end+2:]
+	default:
+		value, _, _ = strings.Cut(rest, "#")
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return "", "", fmt.Errorf("missing value for %s", key)
+		}
+	}
+
+	if tail = strings.TrimSpace(tail); tail != "" && !strings.HasPrefix(tail, "#") {
+		return "", "", fmt.Errorf("unexpected %q after the value of %s", tail, key)
+	}
+	return key, value, nil
+}
+
+// closingQuote returns the index of the quote ending the double-quoted
+// string at the start of s, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// validName reports whether s is a bare TOML key: letters, digits, - and _
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns the value of key in profile, falling back to the top-level
+// setting. An empty profile reads the top-level settings only.
+func (f *File) Get(profile, key string) (string, bool) {
+	if profile != "" {
+		if value, ok := f.lookup(profile, key); ok {
+			return value, true
+		}
+	}
+	return f.lookup("", key)
+}
+
+// lookup returns the value of key set directly in profile
+func (f *File) lookup(profile, key string) (string, bool) {
+	value, found := "", false
+	for _, l := range f.lines {
+		if l.key == key && l.profile == profile {
+			value, found = l.value, true
+		}
+	}
+	return value, found
+}
+
+// Keys returns the names of the settings in the file, in order of
+// appearance and including those of profiles
+func (f *File) Keys() []string {
+	var keys []string
+	for _, l := range f.lines {
+		if l.key != "" && !slices.Contains(keys, l.key) {
+			keys = append(keys, l.key)
+		}
+	}
+	return keys
+}
+
+// HasProfile reports whether the file has a table for profile
+func (f *File) HasProfile(profile string) bool {
+	for _, l := range f.lines {
+		if l.profile == profile && l.key == "" && strings.HasPrefix(strings.TrimSpace(l.text), "[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Set stores value under key in profile, or at the top level when profile
+// is empty. An existing setting is replaced in place; a new one is added
+// after the last setting of its table, creating the table when needed.
+func (f *File) Set(profile, key, value string) error {
+	if !validName(key) {
+		return fmt.Errorf("invalid setting name %q", key)
+	}
+	if profile != "" && !validName(profile) {
+		return fmt.Errorf("invalid profile name %q", profile)
+	}
+	l := line{text: key + " = " + strconv.Quote(value), profile: profile, key: key, value: value}
+
+	// Replace the last definition, which is the one that counts
+	for i := len(f.lines) - 1; i >= 0; i-- {
+		if f.lines[i].key == key && f.lines[i].profile == profile {
+			f.lines[i] = l
+			return nil
+		}
+	}
+
+	// Add to the end of the table, before any blank lines separating it
+	// from the next one
+	end := -1
+	for i, existing := range f.lines {
+		if existing.profile == profile && strings.TrimSpace(existing.text) != "" {
+			end = i
+		}
+	}
+	switch {
+	case end >= 0:
+		f.lines = append(f.lines[:end+1], append([]line{l}, f.lines[end+1:]...)...)
+	case profile == "" && len(f.lines) > 0:
+		f.lines = append([]line{l, {}}, f.lines...)
+	case profile == "":
+		f.lines = []line{l}
+	default:
+		if n := len(f.lines); n > 0 && strings.TrimSpace(f.lines[n-1].text) != "" {
+			f.lines = append(f.lines, line{profile: f.lines[n-1].profile})
+		}
+		f.lines = append(f.lines, line{text: "[" + profilePrefix + profile + "]", profile: profile}, l)
+	}
+	return nil
+}
+
+// String returns the text of the file
+func (f *File) String() string {
+	var b strings.Builder
+	for _, l := range f.lines {
+		b.WriteString(l.text + "\n")
+	}
+	return b.String()
+}
+
+// Save writes the file to path, creating its directory
+func (f *File) Save(path string) error {
+	if err := fsutil.WriteFileAtomic(path, []byte(f.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `# sky settings
+location = "Berlin, DE"
+units = 'imperial'  # for the family in Chicago
+timeout = 20s
+cache = true
+
+[profile.work]
+location = "Tokyo"
+format = "json"
+
+[profile.empty]
+`
+
+func TestParse_Get(t *testing.T) {
+	f, err := Parse(sample)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		profile string
+		key     string
+		want    string
+		wantOK  bool
+	}{
+		{"Double-quoted", "", "location", "Berlin, DE", true},
+		{"Single-quoted with comment", "", "units", "imperial", true},
+		{"Bare", "", "timeout", "20s", true},
+		{"Boolean", "", "cache", "true", true},
+		{"Profile overrides", "work", "location", "Tokyo", true},
+		{"Profile falls back", "work", "units", "imperial", true},
+		{"Profile only", "work", "format", "json", true},
+		{"Profile setting hidden at top level", "", "format", "", false},
+		{"Unknown profile", "home", "location", "Berlin, DE", true},
+		{"Missing", "", "palette", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := f.Get(tt.profile, tt.key)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestParse_Escapes(t *testing.T) {
+	f, err := Parse(`location = "Zürich \"CH\""` + "\n" + `template = 'C:\sky # not a comment'`)
+	require.NoError(t, err)
+
+	value, _ := f.Get("", "location")
+	assert.Equal(t, `Zürich "CH"`, value)
+	value, _ = f.Get("", "template")
+	assert.Equal(t, `C:\sky # not a comment`, value)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"No value", "units", `line 1: expected key = value, got "units"`},
+		{"Empty value", "units =", "line 1: missing value for units"},
+		{"Unterminated string", `units = "metric`, "line 1: unterminated string for units"},
+		{"Trailing text", `units = "metric" imperial`, `line 1: unexpected "imperial" after the value of units`},
+		{"Invalid key", "my units = metric", `line 1: expected key = value, got "my units = metric"`},
+		{"Other table", "\n[colors]", "line 2: unsupported table [colors] (want [profile.NAME])"},
+		{"Unterminated header", "[profile.work", "line 1: unterminated table header [profile.work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.text)
+
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestFile_Keys(t *testing.T) {
+	f, err := Parse(sample)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"location", "units", "timeout", "cache", "format"}, f.Keys())
+}
+
+func TestFile_HasProfile(t *testing.T) {
+	f, err := Parse(sample)
+	require.NoError(t, err)
+
+	assert.True(t, f.HasProfile("work"))
+	assert.True(t, f.HasProfile("empty"))
+	assert.False(t, f.HasProfile("home"))
+}
+
+func TestFile_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		profile string
+		key     string
+		value   string
+		want    string
+	}{
+		{
+			"Replace keeps comments",
+			"# mine\nunits = 'imperial'  # note\n",
+			"", "units", "metric",
+			"# mine\nunits = \"metric\"\n",
+		},
+		{
+			"Add to empty file",
+			"",
+			"", "units", "metric",
+			"units = \"metric\"\n",
+		},
+		{
+			"Add top-level before tables",
+			"units = \"metric\"\n\n[profile.work]\nformat = \"json\"\n",
+			"", "location", "Berlin",
+			"units = \"metric\"\nlocation = \"Berlin\"\n\n[profile.work]\nformat = \"json\"\n",
+		},
+		{
+			"Add top-level to file with only tables",
+			"[profile.work]\nformat = \"json\"\n",
+			"", "units", "metric",
+			"units = \"metric\"\n\n[profile.work]\nformat = \"json\"\n",
+		},
+		{
+			"Add to existing profile",
+			"[profile.work]\nformat = \"json\"\n\n[profile.home]\n",
+			"work", "units", "imperial",
+			"[profile.work]\nformat = \"json\"\nunits = \"imperial\"\n\n[profile.home]\n",
+		},
+		{
+			"Replace in profile only",
+			"units = \"metric\"\n[profile.work]\nunits = \"metric\"\n",
+			"work", "units", "imperial",
+			"units = \"metric\"\n[profile.work]\nunits = \"imperial\"\n",
+		},
+		{
+			"Create profile",
+			"units = \"metric\"\n",
+			"work", "format", "json",
+			"units = \"metric\"\n\n[profile.work]\nformat = \"json\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.text)
+			require.NoError(t, err)
+
+			require.NoError(t, f.Set(tt.profile, tt.key, tt.value))
+
+			assert.Equal(t, tt.want, f.String())
+			value, ok := f.Get(tt.profile, tt.key)
+			assert.True(t, ok)
+			assert.Equal(t, tt.value, value)
+
+			// The result parses back to the same settings
+			reparsed, err := Parse(f.String())
+			require.NoError(t, err)
+			assert.Equal(t, f.String(), reparsed.String())
+		})
+	}
+}
+
+func TestFile_SetInvalidNames(t *testing.T) {
+	f := &File{}
+
+	assert.EqualError(t, f.Set("", "my units", "x"), `invalid setting name "my units"`)
+	assert.EqualError(t, f.Set("a.b", "units", "x"), `invalid profile name "a.b"`)
+}
+
+func TestLoad_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sky", "config.toml")
+
+	f, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Set("", "units", "imperial"))
+	require.NoError(t, f.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	value, _ := loaded.Get("", "units")
+	assert.Equal(t, "imperial", value)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("units\n"), 0o644))
+
+	_, err := Load(path)
+
+	assert.ErrorContains(t, err, path+`: line 1: expected key = value`)
+}
+
+func TestPath(t *testing.T) {
+	path, err := Path(func(key string) string {
+		if key == "XDG_CONFIG_HOME" {
+			return "/home/me/.config"
+		}
+		return ""
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/home/me/.config", "sky", "config.toml"), path)
+}
@@ -0,0 +1,139 @@
+// Package favorites keeps places saved under short aliases, such as @home,
+// together with their resolved coordinates so they never need geocoding again.
+package favorites
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/fsutil"
+)
+
+// Prefix marks an alias where a location is expected, as in sky @home
+const Prefix = "@"
+
+// FileName is the name of the favorites file in the configuration directory
+const FileName = "favorites.json"
+
+// ErrNotFound is returned for an alias that names no favorite
+var ErrNotFound = errors.New("no favorite named")
+
+// Favorite is a location saved under an alias
+type Favorite struct {
+	Alias    string       `json:"alias"`
+	Location api.Location `json:"location"`
+}
+
+// List is the set of saved favorites, in the order they were added
+type List struct {
+	favorites []Favorite
+}
+
+// Load reads the favorites file at path.
+// A missing file is an empty list.
+func Load(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+
+	l := &List{}
+	if err := json.Unmarshal(data, &l.favorites); err != nil {
+		return nil, fmt.Errorf("%s: invalid favorites file: %w", path, err)
+	}
+	return l, nil
+}
+
+// ParseAlias returns the alias named by input when it starts with Prefix
+func ParseAlias(input string) (alias string, ok bool) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, Prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(input, Prefix), true
+}
+
+// ValidAlias reports whether alias may name a favorite: letters, digits, - and _
+func ValidAlias(alias string) bool {
+	if alias == "" {
+		return false
+	}
+	for _, r := range alias {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// All returns the favorites in the order they were added
+func (l *List) All() []Favorite {
+	return l.favorites
+}
+
+// Get returns the location saved under alias. Aliases are case-insensitive.
+func (l *List) Get(alias string) (*api.Location, bool) {
+	if i := l.index(alias); i >= 0 {
+		location := l.favorites[i].Location
+		return &location, true
+	}
+	return nil, false
+}
+
+// Add saves location under alias, replacing any favorite with that alias
+func (l *List) Add(alias string, location api.Location) error {
+	if !ValidAlias(alias) {
+		return fmt.Errorf("invalid alias %q (use letters, digits, - and _)", alias)
+	}
+
+	favorite := Favorite{Alias: alias, Location: location}
+	if i := l.index(alias); i >= 0 {
+		l.favorites[i] = favorite
+		return nil
+	}
+	l.favorites = append(l.favorites, favorite)
+	return nil
+}
+
+// Remove deletes the favorite saved under alias and reports whether it existed
+func (l *List) Remove(alias string) bool {
+	i := l.index(alias)
+	if i < 0 {
+		return false
+	}
+	l.favorites = append(l.favorites[:i], l.favorites[i+1:]...)
+	return true
+}
+
+func (l *List) index(alias string) int {
+	for i, f := range l.favorites {
+		if strings.EqualFold(f.Alias, alias) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Save writes the list to path, creating its directory
+func (l *List) Save(path string) error {
+	favorites := l.favorites
+	if favorites == nil {
+		favorites = []Favorite{}
+	}
+	data, err := json.MarshalIndent(favorites, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to save favorites: %w", err)
+	}
+	return nil
+}
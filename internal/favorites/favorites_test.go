@@ -0,0 +1,116 @@
+package favorites
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+var (
+	berlin      = api.Location{Name: "Berlin", Country: "DE", Latitude: 52.52437, Longitude: 13.41053, Timezone: "Europe/Berlin"}
+	springfield = api.Location{Name: "Springfield", Country: "US", Admin1: "Illinois", Latitude: 39.80172, Longitude: -89.64371}
+)
+
+func TestParseAlias(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"@home", "home", true},
+		{" @work ", "work", true},
+		{"@", "", true},
+		{"home", "", false},
+		{"Berlin, DE", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			alias, ok := ParseAlias(tt.input)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, alias)
+		})
+	}
+}
+
+func TestValidAlias(t *testing.T) {
+	for _, alias := range []string{"home", "work-2", "mum_and_dad", "X"} {
+		assert.True(t, ValidAlias(alias), alias)
+	}
+	for _, alias := range []string{"", "@home", "my home", "zürich", "a;b"} {
+		assert.False(t, ValidAlias(alias), alias)
+	}
+}
+
+func TestList_AddGetRemove(t *testing.T) {
+	l := &List{}
+
+	require.NoError(t, l.Add("home", berlin))
+	require.NoError(t, l.Add("spring", springfield))
+
+	location, ok := l.Get("HOME")
+	require.True(t, ok)
+	assert.Equal(t, berlin, *location)
+
+	// Adding an existing alias replaces it in place
+	require.NoError(t, l.Add("Home", springfield))
+	assert.Equal(t, []Favorite{{"Home", springfield}, {"spring", springfield}}, l.All())
+
+	assert.True(t, l.Remove("home"))
+	assert.False(t, l.Remove("home"))
+	_, ok = l.Get("home")
+	assert.False(t, ok)
+	assert.Len(t, l.All(), 1)
+}
+
+func TestList_GetReturnsCopy(t *testing.T) {
+	l := &List{}
+	require.NoError(t, l.Add("home", berlin))
+
+	location, _ := l.Get("home")
+	location.Name = "Changed"
+
+	again, _ := l.Get("home")
+	assert.Equal(t, "Berlin", again.Name)
+}
+
+func TestList_AddInvalidAlias(t *testing.T) {
+	l := &List{}
+
+	err := l.Add("my home", berlin)
+
+	assert.EqualError(t, err, `invalid alias "my home" (use letters, digits, - and _)`)
+	assert.Empty(t, l.All())
+}
+
+func TestLoad_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sky", FileName)
+
+	// A missing file is an empty list
+	l, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, l.All())
+
+	require.NoError(t, l.Add("home", berlin))
+	require.NoError(t, l.Add("spring", springfield))
+	require.NoError(t, l.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, l.All(), loaded.All())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+	_, err := Load(path)
+
+	assert.ErrorContains(t, err, "invalid favorites file")
+}
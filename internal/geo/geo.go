@@ -0,0 +1,165 @@
+// Package geo parses coordinates written in common location notations.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrNotCoordinates is returned by Parse when the input is not written in any
+// supported coordinate notation and should be treated as a place name
+var ErrNotCoordinates = errors.New("not a coordinate notation")
+
+// Point is a position in decimal degrees
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// String formats the point with hemisphere letters, e.g. 35.6833°N 139.6833°E
+func (p Point) String() string {
+	ns, ew := "N", "E"
+	if p.Latitude < 0 {
+		ns = "S"
+	}
+	if p.Longitude < 0 {
+		ew = "W"
+	}
+	return fmt.Sprintf("%.4f°%s %.4f°%s", math.Abs(p.Latitude), ns, math.Abs(p.Longitude), ew)
+}
+
+// Parse reads a point written as a decimal "lat,lon" pair, a geo: URI,
+// degrees-minutes-seconds, a geohash or a full Open Location Code (plus code).
+// It returns ErrNotCoordinates when s looks like none of these.
+func Parse(s string) (Point, error) {
+	s = strings.TrimSpace(s)
+
+	if rest, ok := cutPrefixFold(s, "geo:"); ok {
+		// RFC 5870: geo:lat,lon[,alt][;params]
+		rest, _, _ = strings.Cut(rest, ";")
+		parts := strings.Split(rest, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return Point{}, fmt.Errorf("invalid geo URI %q", s)
+		}
+		return parseDecimal(parts[0], parts[1])
+	}
+	for _, prefix := range []string{"geohash:", "gh:"} {
+		if rest, ok := cutPrefixFold(s, prefix); ok {
+			return DecodeGeohash(rest)
+		}
+	}
+
+	if m := decimalPattern.FindStringSubmatch(s); m != nil {
+		return parseDecimal(m[1], m[2])
+	}
+	if strings.Contains(s, "+") && isPlusCode(s) {
+		return DecodePlusCode(s)
+	}
+	if m := dmsPattern.FindStringSubmatch(s); m != nil {
+		return parseDMS(m)
+	}
+	if looksLikeGeohash(s) {
+		return DecodeGeohash(s)
+	}
+
+	return Point{}, ErrNotCoordinates
+}
+
+var decimalPattern = regexp.MustCompile(`^([+-]?\d+(?:\.\d+)?)\s*(?:,\s*|\s+)([+-]?\d+(?:\.\d+)?)$`)
+
+// parseDecimal parses and validates a decimal latitude and longitude
+func parseDecimal(lat, lon string) (Point, error) {
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid latitude %q", lat)
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid longitude %q", lon)
+	}
+	return validate(Point{Latitude: latitude, Longitude: longitude})
+}
+
+// dmsComponent matches one angle such as 35°41'22.5"N, N35°41' or -139.69°
+const dmsComponent = `([NSEW])?\s*(-)?(\d+(?:\.\d+)?)\s*[°º]\s*(?:(\d+(?:\.\d+)?)\s*['′]\s*)?(?:(\d+(?:\.\d+)?)\s*(?:"|″|'')\s*)?([NSEW])?`
+
+var dmsPattern = regexp.MustCompile(`(?i)^` + dmsComponent + `\s*[,\s]\s*` + dmsComponent + `$`)
+
+// parseDMS converts the submatches of dmsPattern into a point
+func parseDMS(m []string) (Point, error) {
+	first, firstHemisphere, err := dmsAngle(m[1:7])
+	if err != nil {
+		return Point{}, err
+	}
+	second, secondHemisphere, err := dmsAngle(m[7:13])
+	if err != nil {
+		return Point{}, err
+	}
+
+	isLongitude := func(h string) bool { return h == "E" || h == "W" }
+	isLatitude := func(h string) bool { return h == "N" || h == "S" }
+
+	switch {
+	case isLongitude(firstHemisphere) && !isLongitude(secondHemisphere):
+		return validate(Point{Latitude: second, Longitude: first})
+	case isLatitude(secondHemisphere) && !isLatitude(firstHemisphere):
+		return validate(Point{Latitude: second, Longitude: first})
+	case firstHemisphere != "" && secondHemisphere != "" && isLatitude(firstHemisphere) == isLatitude(secondHemisphere):
+		return Point{}, fmt.Errorf("both angles are in the %s hemisphere", firstHemisphere)
+	}
+	return validate(Point{Latitude: first, Longitude: second})
+}
+
+// dmsAngle converts the groups of one dmsComponent into signed decimal degrees
+// and returns the hemisphere letter, if any
+func dmsAngle(g []string) (float64, string, error) {
+	prefix, minus, deg, min, sec, suffix := g[0], g[1], g[2], g[3], g[4], g[5]
+	if prefix != "" && suffix != "" {
+		return 0, "", fmt.Errorf("angle has two hemisphere letters")
+	}
+	hemisphere := strings.ToUpper(prefix + suffix)
+	if minus != "" && hemisphere != "" {
+		return 0, "", fmt.Errorf("angle has both a sign and a hemisphere letter")
+	}
+
+	degrees, _ := strconv.ParseFloat(deg, 64)
+	var minutes, seconds float64
+	if min != "" {
+		minutes, _ = strconv.ParseFloat(min, 64)
+	}
+	if sec != "" {
+		seconds, _ = strconv.ParseFloat(sec, 64)
+	}
+	if minutes >= 60 || seconds >= 60 {
+		return 0, "", fmt.Errorf("minutes and seconds must be less than 60")
+	}
+
+	angle := degrees + minutes/60 + seconds/3600
+	if minus != "" || hemisphere == "S" || hemisphere == "W" {
+		angle = -angle
+	}
+	return angle, hemisphere, nil
+}
+
+// validate checks that a point lies within the valid coordinate ranges
+func validate(p Point) (Point, error) {
+	if p.Latitude < -90 || p.Latitude > 90 {
+		return Point{}, fmt.Errorf("latitude %.4f is out of range [-90, 90]", p.Latitude)
+	}
+	if p.Longitude < -180 || p.Longitude > 180 {
+		return Point{}, fmt.Errorf("longitude %.4f is out of range [-180, 180]", p.Longitude)
+	}
+	return p, nil
+}
+
+// cutPrefixFold is strings.CutPrefix with case-insensitive matching
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Notations(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantLat float64
+		wantLon float64
+	}{
+		{"Decimal pair", "35.6762,139.6503", 35.6762, 139.6503},
+		{"Decimal pair with space", "  -33.8688, 151.2093 ", -33.8688, 151.2093},
+		{"Decimal pair without comma", "51.5074 -0.1278", 51.5074, -0.1278},
+		{"Integer pair", "0,0", 0, 0},
+		{"geo URI", "geo:48.2010,16.3695;u=35", 48.2010, 16.3695},
+		{"geo URI with altitude", "GEO:37.786971,-122.399677,15", 37.786971, -122.399677},
+		{"DMS with suffixes", "35°41'N 139°41'E", 35 + 41.0/60, 139 + 41.0/60},
+		{"DMS with seconds", `40°26'46"N, 79°58'56"W`, 40 + 26.0/60 + 46.0/3600, -(79 + 58.0/60 + 56.0/3600)},
+		{"DMS with prefixes", "S33°52' E151°12'", -(33 + 52.0/60), 151 + 12.0/60},
+		{"DMS longitude first", "139°41'E 35°41'N", 35 + 41.0/60, 139 + 41.0/60},
+		{"DMS prime symbols", "48°51′24″N 2°21′03″E", 48 + 51.0/60 + 24.0/3600, 2 + 21.0/60 + 3.0/3600},
+		{"Decimal degrees with hemispheres", "35.68°N 139.69°E", 35.68, 139.69},
+		{"Signed decimal degrees", "-22.9°, -43.2°", -22.9, -43.2},
+		{"Geohash", "u4pruydqqvj", 57.64911, 10.40744},
+		{"Geohash with prefix", "gh:xn76urx", 35.6815, 139.7674},
+		{"Plus code", "8FVC9G8F+6X", 47.3655625, 8.5249375},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantLat, p.Latitude, 1e-3)
+			assert.InDelta(t, tt.wantLon, p.Longitude, 1e-3)
+		})
+	}
+}
+
+func TestParse_PlaceNames(t *testing.T) {
+	names := []string{"Tokyo", "New York", "São Paulo", "bern", "Paris 75001", "St. John's", "Springfield, IL", "hakone"}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(name)
+			assert.ErrorIs(t, err, ErrNotCoordinates)
+		})
+	}
+}
+
+func TestParse_PostalCodes(t *testing.T) {
+	// Digits are all in the geohash alphabet; postal codes go to the geocoder
+	codes := []string{"90210", "10115", "75001"}
+
+	for _, code := range codes {
+		t.Run(code, func(t *testing.T) {
+			_, err := Parse(code)
+			assert.ErrorIs(t, err, ErrNotCoordinates)
+		})
+	}
+}
+
+func TestParse_InvalidCoordinates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Latitude out of range", "91,0"},
+		{"Longitude out of range", "0,181"},
+		{"Minutes out of range", "35°75'N 139°41'E"},
+		{"Both latitudes", "35°41'N 39°41'S"},
+		{"Sign and hemisphere", "-35°41'N 139°41'E"},
+		{"Bad geo URI", "geo:48.2"},
+		{"Bad geohash", "gh:xnai"},
+		{"Short plus code", "9G8F+6X"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, ErrNotCoordinates)
+		})
+	}
+}
+
+func TestPoint_String(t *testing.T) {
+	assert.Equal(t, "35.6762°N 139.6503°E", Point{Latitude: 35.6762, Longitude: 139.6503}.String())
+	assert.Equal(t, "33.8688°S 70.6693°W", Point{Latitude: -33.8688, Longitude: -70.6693}.String())
+}
@@ -0,0 +1,73 @@
+package geo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// geohashAlphabet is the base32 alphabet used by geohashes (no a, i, l, o)
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// DecodeGeohash returns the center of the cell identified by a geohash
+func DecodeGeohash(hash string) (Point, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if hash == "" || len(hash) > 12 {
+		return Point{}, fmt.Errorf("invalid geohash %q", hash)
+	}
+
+	latMin, latMax := -90.0, 90.0
+	lonMin, lonMax := -180.0, 180.0
+	even := true
+
+	for _, r := range hash {
+		idx := strings.IndexRune(geohashAlphabet, r)
+		if idx < 0 {
+			return Point{}, fmt.Errorf("invalid geohash %q: unexpected %q", hash, r)
+		}
+
+		// Bits alternate between longitude and latitude, starting with longitude
+		for bit := 4; bit >= 0; bit-- {
+			set := idx&(1<<bit) != 0
+			if even {
+				mid := (lonMin + lonMax) / 2
+				if set {
+					lonMin = mid
+				} else {
+					lonMax = mid
+				}
+			} else {
+				mid := (latMin + latMax) / 2
+				if set {
+					latMin = mid
+				} else {
+					latMax = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return Point{Latitude: (latMin + latMax) / 2, Longitude: (lonMin + lonMax) / 2}, nil
+}
+
+// looksLikeGeohash reports whether a bare word is almost certainly a geohash
+// rather than a place name: lowercase geohash characters mixing digits and
+// letters. All-digit words are postal codes, left to the geocoder.
+func looksLikeGeohash(s string) bool {
+	if len(s) < 5 || len(s) > 12 {
+		return false
+	}
+
+	hasDigit, hasLetter := false, false
+	for _, r := range s {
+		if !strings.ContainsRune(geohashAlphabet, r) {
+			return false
+		}
+		if r >= '0' && r <= '9' {
+			hasDigit = true
+		} else {
+			hasLetter = true
+		}
+	}
+	return hasDigit && hasLetter
+}
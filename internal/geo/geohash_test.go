@@ -0,0 +1,48 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeGeohash(t *testing.T) {
+	tests := []struct {
+		hash    string
+		wantLat float64
+		wantLon float64
+		delta   float64
+	}{
+		{"u4pruydqqvj", 57.64911, 10.40744, 1e-5},
+		{"ezs42", 42.605, -5.603, 1e-2},
+		{"s00000000000", 0, 0, 1e-6},
+		{"XN76URX", 35.6815, 139.7674, 1e-3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hash, func(t *testing.T) {
+			p, err := DecodeGeohash(tt.hash)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantLat, p.Latitude, tt.delta)
+			assert.InDelta(t, tt.wantLon, p.Longitude, tt.delta)
+		})
+	}
+}
+
+func TestDecodeGeohash_Invalid(t *testing.T) {
+	for _, hash := range []string{"", "abc", "u4pruydqqvjxx"} {
+		_, err := DecodeGeohash(hash)
+		assert.Error(t, err, "hash=%q", hash)
+	}
+}
+
+func TestLooksLikeGeohash(t *testing.T) {
+	assert.True(t, looksLikeGeohash("u4pruydqqvj"))
+	assert.True(t, looksLikeGeohash("xn76u"))
+	assert.False(t, looksLikeGeohash("bern"), "too short")
+	assert.False(t, looksLikeGeohash("berne"), "no digit")
+	assert.False(t, looksLikeGeohash("90210"), "no letter")
+	assert.False(t, looksLikeGeohash("tokyo1"), "contains o")
+	assert.False(t, looksLikeGeohash("XN76URX"), "uppercase needs a prefix")
+}
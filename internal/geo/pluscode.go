@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// plusCodeAlphabet is the Open Location Code digit set
+	plusCodeAlphabet = "23456789CFGHJMPQRVWX"
+
+	// plusCodeSeparatorPosition is the index of '+' in a full plus code
+	plusCodeSeparatorPosition = 8
+
+	// plusCodePairLength is the number of digits encoded as lat/lon pairs
+	plusCodePairLength = 10
+
+	// plusCodeMaxLength is the number of significant digits that are decoded
+	plusCodeMaxLength = 15
+)
+
+// isPlusCode reports whether s is shaped like an Open Location Code
+func isPlusCode(s string) bool {
+	s = strings.ToUpper(s)
+	sep := strings.IndexByte(s, '+')
+	if sep < 2 || sep > plusCodeSeparatorPosition || sep%2 != 0 || strings.Count(s, "+") != 1 {
+		return false
+	}
+	for _, r := range strings.Replace(s, "+", "", 1) {
+		if r != '0' && !strings.ContainsRune(plusCodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodePlusCode returns the center of the area identified by a full Open Location Code,
+// such as 8Q7XMP6R+2W. Short codes need a reference location and are rejected.
+func DecodePlusCode(code string) (Point, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !isPlusCode(code) {
+		return Point{}, fmt.Errorf("invalid plus code %q", code)
+	}
+
+	sep := strings.IndexByte(code, '+')
+	if sep < plusCodeSeparatorPosition {
+		return Point{}, fmt.Errorf("short plus code %q needs a reference location; use the full code", code)
+	}
+
+	head, tail := code[:sep], code[sep+1:]
+	if pad := strings.IndexByte(head, '0'); pad >= 0 {
+		// Padded codes such as 8FVC0000+ cover larger areas
+		if pad%2 != 0 || strings.Trim(head[pad:], "0") != "" || tail != "" {
+			return Point{}, fmt.Errorf("invalid plus code %q: bad padding", code)
+		}
+		head = head[:pad]
+	}
+	if len(tail) == 1 {
+		return Point{}, fmt.Errorf("invalid plus code %q: single digit after separator", code)
+	}
+	if strings.ContainsRune(tail, '0') {
+		return Point{}, fmt.Errorf("invalid plus code %q: padding after separator", code)
+	}
+
+	digits := head + tail
+	if len(digits) > plusCodeMaxLength {
+		digits = digits[:plusCodeMaxLength]
+	}
+
+	lat, lon := -90.0, -180.0
+	latRes, lonRes := 400.0, 400.0
+
+	i := 0
+	for ; i < len(digits) && i < plusCodePairLength; i += 2 {
+		latRes /= 20
+		lonRes /= 20
+		lat += float64(strings.IndexByte(plusCodeAlphabet, digits[i])) * latRes
+		lon += float64(strings.IndexByte(plusCodeAlphabet, digits[i+1])) * lonRes
+	}
+	for ; i < len(digits); i++ {
+		// Grid refinement: 5 rows by 4 columns per digit
+		d := strings.IndexByte(plusCodeAlphabet, digits[i])
+		latRes /= 5
+		lonRes /= 4
+		lat += float64(d/4) * latRes
+		lon += float64(d%4) * lonRes
+	}
+
+	if lat >= 90 || lon >= 180 {
+		return Point{}, fmt.Errorf("invalid plus code %q: out of range", code)
+	}
+	return Point{
+		Latitude:  math.Min(lat+latRes/2, 90),
+		Longitude: lon + lonRes/2,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePlusCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantLat float64
+		wantLon float64
+		delta   float64
+	}{
+		{"8FVC9G8F+6X", 47.3655625, 8.5249375, 1e-7},
+		{"8fvc9g8f+6x", 47.3655625, 8.5249375, 1e-7},
+		{"8Q7XMP6R+2W", 35.66, 139.74, 1e-2},
+		{"8FVC0000+", 47.5, 8.5, 1e-9},
+		{"8FVC9G8F+6XQQ", 47.3655, 8.5248, 1e-3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			p, err := DecodePlusCode(tt.code)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantLat, p.Latitude, tt.delta)
+			assert.InDelta(t, tt.wantLon, p.Longitude, tt.delta)
+		})
+	}
+}
+
+func TestDecodePlusCode_Invalid(t *testing.T) {
+	codes := []string{
+		"",
+		"8FVC9G8F",
+		"8FVC9G8F+6",
+		"9G8F+6X",
+		"8FV00000+",
+		"8FVC0000+6X",
+		"8FVC9G8F+6A",
+		"8FVC+9G8F+6X",
+	}
+
+	for _, code := range codes {
+		_, err := DecodePlusCode(code)
+		assert.Error(t, err, "code=%q", code)
+	}
+}
+
+func TestIsPlusCode(t *testing.T) {
+	assert.True(t, isPlusCode("8FVC9G8F+6X"))
+	assert.True(t, isPlusCode("9G8F+6X"))
+	assert.False(t, isPlusCode("Tokyo+Osaka"))
+	assert.False(t, isPlusCode("C+"))
+}
@@ -0,0 +1,344 @@
+// Package lineedit reads lines from a terminal in raw mode with Emacs-style
+// editing keys, history browsing and tab completion.
+package lineedit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInterrupted is returned when Ctrl+C discards the line being edited
+var ErrInterrupted = errors.New("interrupted")
+
+// Control keys understood by the editor
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyLineFeed  = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// Editor reads lines typed on a terminal
+type Editor struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// History is browsed with the up and down arrows; nil disables it.
+	// ReadLine does not add lines to it.
+	History *History
+
+	// Complete returns the completions of the text before the cursor,
+	// each replacing all of it; nil disables tab completion
+	Complete func(before string) []string
+
+	// Raw switches the terminal to raw mode for the duration of ReadLine
+	// and returns a function restoring it; nil when the input needs no switch
+	Raw func() (restore func(), err error)
+
+	// keys receives the key read in the background; reading is set while
+	// a read is pending, possibly left over from a cancelled ReadLine
+	keys    chan key
+	reading bool
+}
+
+// key is a rune read from the input, or the error that ended the input
+type key struct {
+	r   rune
+	err error
+}
+
+// New returns an Editor reading keys from in and echoing to out
+func New(in io.Reader, out io.Writer) *Editor {
+	return &Editor{in: bufio.NewReader(in), out: out, keys: make(chan key, 1)}
+}
+
+// line is the state of the line being edited
+type line struct {
+	prompt string
+	buf    []rune
+	pos    int
+
+	// history is the index of the history entry shown; len(entries) is the new line
+	history int
+	draft   []rune
+
+	// tabbed is set after a Tab that could not complete, so a second one lists the candidates
+	tabbed bool
+}
+
+// ReadLine shows prompt and returns the line typed up to Enter.
+// It returns io.EOF for Ctrl+D on an empty line or at the end of the input,
+// and ErrInterrupted for Ctrl+C. When ctx is cancelled, it restores the
+// terminal and returns the context's error.
+func (e *Editor) ReadLine(ctx context.Context, prompt string) (string, error) {
+	if e.Raw != nil {
+		restore, err := e.Raw()
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+
+	l := &line{prompt: prompt}
+	if e.History != nil {
+		l.history = len(e.History.Entries())
+	}
+	e.refresh(l)
+
+	for {
+		r, err := e.readRune(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Fprint(e.out, "\r\n")
+				return "", ctx.Err()
+			}
+			if len(l.buf) > 0 && errors.Is(err, io.EOF) {
+				fmt.Fprint(e.out, "\r\n")
+				return string(l.buf), nil
+			}
+			return "", err
+		}
+
+		tabbed := l.tabbed
+		l.tabbed = false
+
+		switch r {
+		case keyEnter, keyLineFeed:
+			fmt.Fprint(e.out, "\r\n")
+			return string(l.buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(l.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			l.deleteAt(l.pos)
+		case keyBackspace, keyDelete:
+			if l.pos > 0 {
+				l.pos--
+				l.deleteAt(l.pos)
+			}
+		case keyCtrlA:
+			l.pos = 0
+		case keyCtrlE:
+			l.pos = len(l.buf)
+		case keyCtrlB:
+			l.pos = max(0, l.pos-1)
+		case keyCtrlF:
+			l.pos = min(len(l.buf), l.pos+1)
+		case keyCtrlK:
+			l.buf = l.buf[:l.pos]
+		case keyCtrlU:
+			l.buf = append([]rune{}, l.buf[l.pos:]...)
+			l.pos = 0
+		case keyCtrlW:
+			start := l.pos
+			for start > 0 && l.buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && l.buf[start-1] != ' ' {
+				start--
+			}
+			l.buf = append(l.buf[:start], l.buf[l.pos:]...)
+			l.pos = start
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			e.browse(l, -1)
+		case keyCtrlN:
+			e.browse(l, 1)
+		case keyTab:
+			e.complete(l, tabbed)
+		case keyEscape:
+			e.escape(ctx, l)
+		default:
+			if r < ' ' {
+				continue
+			}
+			l.buf = append(l.buf[:l.pos], append([]rune{r}, l.buf[l.pos:]...)...)
+			l.pos++
+		}
+		e.refresh(l)
+	}
+}
+
+// escape handles the escape sequences sent by arrow, Home, End and Delete keys
+func (e *Editor) escape(ctx context.Context, l *line) {
+	next, err := e.readRune(ctx)
+	if err != nil || next != '[' && next != 'O' {
+		return
+	}
+
+	// Read parameters up to the final byte of the sequence
+	var params strings.Builder
+	final := rune(0)
+	for {
+		r, err := e.readRune(ctx)
+		if err != nil {
+			return
+		}
+		if r >= 0x40 && r <= 0x7e {
+			final = r
+			break
+		}
+		params.WriteRune(r)
+	}
+
+	switch final {
+	case 'A':
+		e.browse(l, -1)
+	case 'B':
+		e.browse(l, 1)
+	case 'C':
+		l.pos = min(len(l.buf), l.pos+1)
+	case 'D':
+		l.pos = max(0, l.pos-1)
+	case 'H':
+		l.pos = 0
+	case 'F':
+		l.pos = len(l.buf)
+	case '~':
+		switch params.String() {
+		case "1", "7":
+			l.pos = 0
+		case "4", "8":
+			l.pos = len(l.buf)
+		case "3":
+			l.deleteAt(l.pos)
+		}
+	}
+}
+
+// readRune returns the next rune of the input, or ctx's error once ctx is
+// cancelled. The rune is read in the background because a read from a
+// terminal cannot be interrupted; a read outliving ReadLine delivers its
+// rune to the next call.
+func (e *Editor) readRune(ctx context.Context) (rune, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if !e.reading {
+		e.reading = true
+		go func() {
+			r, _, err := e.in.ReadRune()
+			e.keys <- key{r, err}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case k := <-e.keys:
+		e.reading = false
+		return k.r, k.err
+	}
+}
+
+// browse moves through the history by step entries
+func (e *Editor) browse(l *line, step int) {
+	if e.History == nil {
+		return
+	}
+	entries := e.History.Entries()
+	next := l.history + step
+	if next < 0 || next > len(entries) {
+		return
+	}
+
+	// Keep the line being typed to come back to it
+	if l.history == len(entries) {
+		l.draft = append([]rune{}, l.buf...)
+	}
+	l.history = next
+	if next == len(entries) {
+		l.buf = append([]rune{}, l.draft...)
+	} else {
+		l.buf = []rune(entries[next])
+	}
+	l.pos = len(l.buf)
+}
+
+// complete replaces the text before the cursor with its completion, or with
+// the prefix shared by all candidates. A second Tab lists the candidates
+// when they share nothing more.
+func (e *Editor) complete(l *line, tabbed bool) {
+	if e.Complete == nil {
+		return
+	}
+	before := string(l.buf[:l.pos])
+	candidates := e.Complete(before)
+
+	replacement := ""
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+		return
+	case 1:
+		replacement = candidates[0]
+	default:
+		replacement = commonPrefix(candidates)
+	}
+
+	if len([]rune(replacement)) > len([]rune(before)) {
+		rest := l.buf[l.pos:]
+		l.buf = append([]rune(replacement), rest...)
+		l.pos = len([]rune(replacement))
+		return
+	}
+
+	if tabbed {
+		fmt.Fprint(e.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+		return
+	}
+	l.tabbed = true
+	fmt.Fprint(e.out, "\a")
+}
+
+// refresh redraws the prompt and the line and places the cursor
+func (e *Editor) refresh(l *line) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", l.prompt, string(l.buf))
+	if back := len(l.buf) - l.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// deleteAt removes the rune at i, if any
+func (l *line) deleteAt(i int) {
+	if i < len(l.buf) {
+		l.buf = append(l.buf[:i], l.buf[i+1:]...)
+	}
+}
+
+// commonPrefix returns the longest prefix shared by all of words
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		r := []rune(w)
+		n := 0
+		for n < len(prefix) && n < len(r) && prefix[n] == r[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
@@ -0,0 +1,203 @@
+package lineedit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditor_ReadLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain", "Tokyo\r", "Tokyo"},
+		{"Line feed", "Tokyo\n", "Tokyo"},
+		{"Backspace", "Tokyp\x7fo\r", "Tokyo"},
+		{"Insert after moving left", "Tkyo\x1b[D\x1b[D\x1b[Do\r", "Tokyo"},
+		{"Home and end", "okyo\x01T\x05!\r", "Tokyo!"},
+		{"Escape home and end keys", "okyo\x1b[HT\x1b[F!\r", "Tokyo!"},
+		{"Delete key", "XTokyo\x01\x1b[3~\r", "Tokyo"},
+		{"Ctrl+D deletes under the cursor", "XTokyo\x01\x04\r", "Tokyo"},
+		{"Kill to end", "Tokyo, JP\x02\x02\x02\x02\x0b\r", "Tokyo"},
+		{"Kill to start", "New York\x02\x02\x02\x02\x15\r", "York"},
+		{"Delete word", "New York  \x17\r", "New "},
+		{"Unicode", "Zurich\x7f\x7f\x7f\x7f\x7fürich\r", "Zürich"},
+		{"Ignores other control keys", "To\x07kyo\r", "Tokyo"},
+		{"End of input", "Tokyo", "Tokyo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(strings.NewReader(tt.input), io.Discard)
+
+			line, err := e.ReadLine(context.Background(), "> ")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, line)
+		})
+	}
+}
+
+func TestEditor_ReadLineEnds(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"Ctrl+D on an empty line", "\x04", io.EOF},
+		{"Ctrl+C", "Tok\x03", ErrInterrupted},
+		{"Empty input", "", io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(strings.NewReader(tt.input), io.Discard)
+
+			_, err := e.ReadLine(context.Background(), "> ")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEditor_Echo(t *testing.T) {
+	var out bytes.Buffer
+	e := New(strings.NewReader("ab\x1b[D\r"), &out)
+
+	_, err := e.ReadLine(context.Background(), "> ")
+
+	require.NoError(t, err)
+	assert.Equal(t, "\r> \x1b[K\r> a\x1b[K\r> ab\x1b[K\r> ab\x1b[K\x1b[1D\r\n", out.String())
+}
+
+func TestEditor_History(t *testing.T) {
+	h := NewHistory(0)
+	h.Add("Tokyo")
+	h.Add("London")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Previous", "\x1b[A\r", "London"},
+		{"Oldest", "\x1b[A\x1b[A\x1b[A\r", "Tokyo"},
+		{"Back to the draft", "Par\x1b[A\x1b[A\x1b[B\x1b[B\r", "Par"},
+		{"Ctrl+P and Ctrl+N", "\x10\x10\x0e\r", "London"},
+		{"Edit an entry", "\x1b[A\x7f\x7f\x7f\x7f\x7fima\r", "Lima"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(strings.NewReader(tt.input), io.Discard)
+			e.History = h
+
+			line, err := e.ReadLine(context.Background(), "> ")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, line)
+		})
+	}
+	assert.Equal(t, []string{"Tokyo", "London"}, h.Entries())
+}
+
+func TestEditor_Complete(t *testing.T) {
+	complete := func(before string) []string {
+		var result []string
+		for _, word := range []string{"Paris", "Parma", "Tokyo", ":hourly", ":help"} {
+			if strings.HasPrefix(strings.ToLower(word), strings.ToLower(before)) {
+				result = append(result, word)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Single candidate", "to\t\r", "Tokyo"},
+		{"Common prefix", "P\t\r", "Par"},
+		{"Extend after the prefix", "P\ti\t\r", "Paris"},
+		{"No candidates", "x\t\r", "x"},
+		{"Keeps text after the cursor", "to!\x1b[D\t\r", "Tokyo!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(strings.NewReader(tt.input), io.Discard)
+			e.Complete = complete
+
+			line, err := e.ReadLine(context.Background(), "> ")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, line)
+		})
+	}
+}
+
+func TestEditor_CompleteLists(t *testing.T) {
+	var out bytes.Buffer
+	e := New(strings.NewReader(":h\t\t\r"), &out)
+	e.Complete = func(before string) []string { return []string{":hourly", ":help"} }
+
+	line, err := e.ReadLine(context.Background(), "> ")
+
+	require.NoError(t, err)
+	assert.Equal(t, ":h", line)
+	assert.Contains(t, out.String(), "\r\n:hourly  :help\r\n")
+}
+
+func TestEditor_Raw(t *testing.T) {
+	raw, restored := false, false
+	e := New(strings.NewReader("x\r"), io.Discard)
+	e.Raw = func() (func(), error) {
+		raw = true
+		return func() { restored = true }, nil
+	}
+
+	_, err := e.ReadLine(context.Background(), "> ")
+
+	require.NoError(t, err)
+	assert.True(t, raw)
+	assert.True(t, restored)
+}
+
+func TestEditor_Cancel(t *testing.T) {
+	in, typing := io.Pipe()
+	defer typing.Close()
+	restored := false
+	e := New(in, io.Discard)
+	e.Raw = func() (func(), error) {
+		return func() { restored = true }, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancel while a key is being waited for
+	go cancel()
+	_, err := e.ReadLine(ctx, "> ")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, restored)
+
+	// A read left pending delivers its key to the next line
+	go typing.Write([]byte("yo\r"))
+	line, err := e.ReadLine(context.Background(), "> ")
+
+	require.NoError(t, err)
+	assert.Equal(t, "yo", line)
+}
+
+func TestCommonPrefix(t *testing.T) {
+	assert.Equal(t, "Par", commonPrefix([]string{"Paris", "Parma"}))
+	assert.Equal(t, "Zür", commonPrefix([]string{"Zürich", "Zürs"}))
+	assert.Equal(t, "", commonPrefix([]string{"Paris", "Tokyo"}))
+}
@@ -0,0 +1,72 @@
+package lineedit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/fsutil"
+)
+
+// DefaultHistorySize is the number of lines a History keeps by default
+const DefaultHistorySize = 500
+
+// History is the list of lines entered earlier, oldest first
+type History struct {
+	entries []string
+	size    int
+}
+
+// NewHistory returns an empty history keeping at most size lines
+func NewHistory(size int) *History {
+	return &History{size: size}
+}
+
+// LoadHistory reads a history file written by Save, keeping at most size lines.
+// A missing file is an empty history.
+func LoadHistory(path string, size int) (*History, error) {
+	h := NewHistory(size)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		h.Add(line)
+	}
+	return h, nil
+}
+
+// Add appends line, skipping blank lines and repeats of the last line
+func (h *History) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || len(h.entries) > 0 && h.entries[len(h.entries)-1] == line {
+		return
+	}
+	h.entries = append(h.entries, line)
+	if h.size > 0 && len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+}
+
+// Entries returns the lines, oldest first
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Save writes the history to path, one line per entry, creating its directory.
+// The file is replaced at once, so sessions saving together never mix lines.
+func (h *History) Save(path string) error {
+	var b strings.Builder
+	for _, line := range h.entries {
+		b.WriteString(line + "\n")
+	}
+	if err := fsutil.WriteFileAtomic(path, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
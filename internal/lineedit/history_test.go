@@ -0,0 +1,59 @@
+package lineedit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Add(t *testing.T) {
+	h := NewHistory(3)
+
+	for _, line := range []string{"Tokyo", " ", "Tokyo", "London ", "Paris", "Lima"} {
+		h.Add(line)
+	}
+
+	assert.Equal(t, []string{"London", "Paris", "Lima"}, h.Entries())
+}
+
+func TestHistory_LoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sky", "history")
+
+	// A missing file is an empty history
+	h, err := LoadHistory(path, 10)
+	require.NoError(t, err)
+	assert.Empty(t, h.Entries())
+
+	h.Add("Tokyo")
+	h.Add(":units imperial")
+	require.NoError(t, h.Save(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Tokyo\n:units imperial\n", string(data))
+
+	loaded, err := LoadHistory(path, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{":units imperial"}, loaded.Entries())
+}
+
+func TestHistory_SaveReplaces(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history")
+	require.NoError(t, os.WriteFile(path, []byte("Paris\nBerlin\n"), 0o600))
+	h := NewHistory(0)
+	h.Add("Tokyo")
+
+	require.NoError(t, h.Save(path))
+
+	// The old file is replaced whole, without leftover temporary files
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Tokyo\n", string(data))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// FormatAirQuality formats air quality data for display,
+// drawing AQI categories in their color bands
+func FormatAirQuality(location *api.Location, aq *api.AirQuality, style Style) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", locationHeader(location))
+	fmt.Fprintf(&b, "US AQI: %d %s\n", aq.USAQI, formatAQICategory(api.USAQICategory(aq.USAQI), style))
+	fmt.Fprintf(&b, "European AQI: %d %s\n", aq.EuropeanAQI, formatAQICategory(api.EuropeanAQICategory(aq.EuropeanAQI), style))
+	fmt.Fprintf(&b, "PM2.5: %.1f µg/m³\n", aq.PM25)
+	fmt.Fprintf(&b, "PM10: %.1f µg/m³\n", aq.PM10)
+	fmt.Fprintf(&b, "Ozone: %.1f µg/m³\n", aq.Ozone)
+	fmt.Fprintf(&b, "NO₂: %.1f µg/m³\n", aq.NitrogenDioxide)
+	fmt.Fprintf(&b, "SO₂: %.1f µg/m³\n", aq.SulphurDioxide)
+	fmt.Fprintf(&b, "CO: %.1f µg/m³\n", aq.CarbonMonoxide)
+
+	if len(aq.Pollen) > 0 {
+		counts := make([]string, 0, len(aq.Pollen))
+		for _, p := range aq.Pollen {
+			counts = append(counts, fmt.Sprintf("%s %.1f", p.Type, p.Count))
+		}
+		fmt.Fprintf(&b, "Pollen (grains/m³): %s\n", strings.Join(counts, ", "))
+	}
+
+	return b.String()
+}
+
+// formatAQICategory formats an AQI category label in its band color
+func formatAQICategory(category api.AQICategory, style Style) string {
+	return style.AQIBand(category.Level, "("+category.Label+")")
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAirQuality_Typical(t *testing.T) {
+	location := &api.Location{Name: "Berlin", Country: "DE"}
+	aq := &api.AirQuality{
+		PM25:            8.1,
+		PM10:            12.4,
+		Ozone:           60,
+		NitrogenDioxide: 14.2,
+		SulphurDioxide:  2.1,
+		CarbonMonoxide:  210,
+		EuropeanAQI:     25,
+		USAQI:           42,
+		Pollen:          []api.PollenCount{{Type: "birch", Count: 12}, {Type: "grass", Count: 3.5}},
+	}
+
+	output := FormatAirQuality(location, aq, Style{})
+
+	assert.Equal(t, "Berlin, DE\n"+
+		"US AQI: 42 (Good)\n"+
+		"European AQI: 25 (Fair)\n"+
+		"PM2.5: 8.1 µg/m³\n"+
+		"PM10: 12.4 µg/m³\n"+
+		"Ozone: 60.0 µg/m³\n"+
+		"NO₂: 14.2 µg/m³\n"+
+		"SO₂: 2.1 µg/m³\n"+
+		"CO: 210.0 µg/m³\n"+
+		"Pollen (grains/m³): birch 12.0, grass 3.5\n", output)
+}
+
+func TestFormatAirQuality_NoPollen(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+
+	output := FormatAirQuality(location, &api.AirQuality{USAQI: 160}, Style{})
+
+	assert.Contains(t, output, "US AQI: 160 (Unhealthy)\n")
+	assert.NotContains(t, output, "Pollen")
+}
+
+func TestFormatAirQuality_ColorBands(t *testing.T) {
+	location := &api.Location{Name: "Delhi", Country: "IN"}
+
+	output := FormatAirQuality(location, &api.AirQuality{USAQI: 320, EuropeanAQI: 10}, Style{Mode: Color256})
+
+	assert.Contains(t, output, "US AQI: 320 \x1b[38;5;88m(Hazardous)\x1b[0m\n")
+	assert.Contains(t, output, "European AQI: 10 \x1b[38;5;40m(Good)\x1b[0m\n")
+}
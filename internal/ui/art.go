@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// artWidth is the width of the column reserved for weather art
+const artWidth = 13
+
+// Weather art in the style of wttr.in. It is plain ASCII so it renders on
+// terminals and SSH sessions without emoji fonts.
+var (
+	artSun = []string{
+		"    \\   /",
+		"     .-.",
+		"  - (   ) -",
+		"     `-'",
+		"    /   \\",
+	}
+	artMoon = []string{
+		"      .--.",
+		"     / .-'",
+		"    | (",
+		"     \\ '-.",
+		"      '--'",
+	}
+	artPartlyCloudyDay = []string{
+		"   \\  /",
+		" _ /\"\".-.",
+		"   \\_(   ).",
+		"   /(___(__)",
+		"",
+	}
+	artPartlyCloudyNight = []string{
+		"    .-.",
+		"   ( (.-.",
+		"    '(   ).",
+		"    (___(__)",
+		"",
+	}
+	artCloudy = []string{
+		"",
+		"     .--.",
+		"  .-(    ).",
+		" (___.__)__)",
+		"",
+	}
+	artFog = []string{
+		"",
+		" _ - _ - _ -",
+		"  _ - _ - _",
+		" _ - _ - _ -",
+		"",
+	}
+	artRain = []string{
+		"     .-.",
+		"    (   ).",
+		"   (___(__)",
+		"    ' ' ' '",
+		"   ' ' ' '",
+	}
+	artSnow = []string{
+		"     .-.",
+		"    (   ).",
+		"   (___(__)",
+		"    *  *  *",
+		"   *  *  *",
+	}
+	artThunder = []string{
+		"     .-.",
+		"    (   ).",
+		"   (___(__)",
+		"    /_  /_",
+		"     /   /",
+	}
+	artUnknown = []string{
+		"    .-.",
+		"     __)",
+		"    (",
+		"     '",
+		"     o",
+	}
+)
+
+// WeatherArt returns the lines of the art for a weather code.
+// Clear and partly cloudy skies show the moon at night.
+func WeatherArt(code int, isDay bool) []string {
+	switch api.WeatherCodeGroup(code) {
+	case api.GroupClear:
+		if code != 0 {
+			return dayOrNight(isDay, artPartlyCloudyDay, artPartlyCloudyNight)
+		}
+		return dayOrNight(isDay, artSun, artMoon)
+	case api.GroupCloudy:
+		if code == 2 {
+			return dayOrNight(isDay, artPartlyCloudyDay, artPartlyCloudyNight)
+		}
+		return artCloudy
+	case api.GroupFog:
+		return artFog
+	case api.GroupRain:
+		return artRain
+	case api.GroupSnow:
+		return artSnow
+	case api.GroupThunder:
+		return artThunder
+	default:
+		return artUnknown
+	}
+}
+
+func dayOrNight(isDay bool, day, night []string) []string {
+	if isDay {
+		return day
+	}
+	return night
+}
+
+// FormatWeatherArt formats the weather data next to art of the conditions
+func FormatWeatherArt(location *api.Location, weather *api.Weather, style Style) string {
+	return formatArt(location, weather, artSummary(weather, style), style)
+}
+
+// FormatWeatherArtDetail formats all available current conditions next to art of the conditions
+func FormatWeatherArtDetail(location *api.Location, weather *api.Weather, style Style) string {
+	return formatArt(location, weather, artSummary(weather, style)+formatDetail(weather, style), style)
+}
+
+// artSummary formats the summary lines shown beside the art, without emoji
+func artSummary(weather *api.Weather, style Style) string {
+	units := weather.Units
+	return fmt.Sprintf("%s\nTemp: %s\nFeels like: %s\n",
+		style.Condition(weather.WeatherCode, weather.WeatherCodeDesc),
+		styleTemperature(formatTemperature(weather.Temperature, units), weather.Temperature, units, style),
+		styleTemperature(formatTemperature(weather.ApparentTemp, units), weather.ApparentTemp, units, style),
+	)
+}
+
+// formatArt places the lines of data to the right of the art for the weather
+func formatArt(location *api.Location, weather *api.Weather, data string, style Style) string {
+	art := WeatherArt(weather.WeatherCode, weather.IsDay)
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+
+	var b strings.Builder
+	b.WriteString(locationHeader(location) + "\n")
+	for i := range max(len(art), len(lines)) {
+		var picture, text string
+		if i < len(art) {
+			picture = art[i]
+		}
+		if i < len(lines) {
+			text = lines[i]
+		}
+
+		// Pad after coloring so escape codes do not count toward the width
+		padding := strings.Repeat(" ", max(0, artWidth-utf8.RuneCountInString(picture)))
+		if picture != "" {
+			picture = style.Condition(weather.WeatherCode, picture)
+		}
+		line := picture + padding + "  " + text
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	b.WriteString(staleMarker(weather))
+
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherArt(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  int
+		isDay bool
+		want  []string
+	}{
+		{"Clear day", 0, true, artSun},
+		{"Clear night", 0, false, artMoon},
+		{"Mainly clear day", 1, true, artPartlyCloudyDay},
+		{"Partly cloudy night", 2, false, artPartlyCloudyNight},
+		{"Overcast", 3, false, artCloudy},
+		{"Fog", 48, true, artFog},
+		{"Drizzle", 53, true, artRain},
+		{"Showers", 81, false, artRain},
+		{"Snow", 75, true, artSnow},
+		{"Thunderstorm", 99, true, artThunder},
+		{"Unknown", 42, true, artUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WeatherArt(tt.code, tt.isDay))
+		})
+	}
+}
+
+func TestWeatherArt_FitsColumn(t *testing.T) {
+	for _, art := range [][]string{artSun, artMoon, artPartlyCloudyDay, artPartlyCloudyNight, artCloudy, artFog, artRain, artSnow, artThunder, artUnknown} {
+		assert.Len(t, art, 5)
+		for _, line := range art {
+			assert.LessOrEqual(t, len(line), artWidth, line)
+		}
+	}
+}
+
+func TestFormatWeatherArt_Typical(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	weather := &api.Weather{
+		Temperature:     15.5,
+		ApparentTemp:    14.2,
+		WeatherCode:     0,
+		WeatherCodeDesc: "Clear",
+		IsDay:           true,
+	}
+
+	output := FormatWeatherArt(location, weather, Style{})
+
+	assert.Equal(t, "Tokyo, JP\n"+
+		"    \\   /      Clear\n"+
+		"     .-.       Temp: 15.5°C\n"+
+		"  - (   ) -    Feels like: 14.2°C\n"+
+		"     `-'\n"+
+		"    /   \\\n", output)
+}
+
+func TestFormatWeatherArtDetail_ExtendsPastArt(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	weather := &api.Weather{WeatherCode: 3, WeatherCodeDesc: "Overcast", Humidity: 80, Stale: true}
+
+	output := FormatWeatherArtDetail(location, weather, Style{})
+
+	assert.Contains(t, output, "\n               Overcast\n")
+	assert.Contains(t, output, "\n (___.__)__)   Humidity: 80%\n")
+	assert.Contains(t, output, "\n               Daylight: no\n(cached)\n")
+}
+
+func TestFormatWeatherArt_Color(t *testing.T) {
+	location := &api.Location{Name: "Oslo", Country: "NO"}
+	weather := &api.Weather{WeatherCode: 71, WeatherCodeDesc: "Slight snow"}
+
+	output := FormatWeatherArt(location, weather, Style{Mode: Color16})
+
+	assert.Contains(t, output, "\x1b[37m     .-.\x1b[0m       \x1b[37mSlight snow\x1b[0m\n")
+}
@@ -0,0 +1,248 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+const (
+	// DefaultChartWidth is used when the terminal width is unknown
+	DefaultChartWidth = 80
+
+	// chartAxisWidth is the width of the y-axis labels and the axis line
+	chartAxisWidth = 9
+
+	// chartTempRows and chartRainRows are the heights of the two plots
+	chartTempRows = 3
+	chartRainRows = 2
+)
+
+// lowerBlocks fill 0 to 8 eighths of a cell from the bottom
+var lowerBlocks = []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// hourLabelSteps are the spacings, in hours, of the hourly axis labels
+var hourLabelSteps = []int{3, 6, 12, 24}
+
+// chartColumn is the data drawn in one column of a chart
+type chartColumn struct {
+	// times are the times of the forecast points merged into the column
+	times []time.Time
+
+	// low and high bound the temperatures of the column
+	low, high float64
+
+	probability int
+}
+
+// FormatHourlyChart formats an hourly forecast as a temperature plot with
+// precipitation probability bars underneath, fitted to width columns
+func FormatHourlyChart(location *api.Location, forecast *api.HourlyForecast, width int, style Style) string {
+	columns := make([]chartColumn, len(forecast.Hours))
+	for i, hour := range forecast.Hours {
+		columns[i] = chartColumn{
+			times:       []time.Time{hour.Time},
+			low:         hour.Temperature,
+			high:        hour.Temperature,
+			probability: hour.PrecipitationProbability,
+		}
+	}
+
+	header := fmt.Sprintf("%s\nNext %d hours:\n", locationHeader(location), len(forecast.Hours))
+	return header + renderChart(columns, forecast.Units, false, width, style)
+}
+
+// FormatDailyChart formats a daily forecast as bars spanning each day's low
+// and high with precipitation probability bars underneath, fitted to width columns
+func FormatDailyChart(location *api.Location, forecast *api.DailyForecast, width int, style Style) string {
+	columns := make([]chartColumn, len(forecast.Days))
+	for i, day := range forecast.Days {
+		columns[i] = chartColumn{
+			times:       []time.Time{day.Date},
+			low:         day.TemperatureMin,
+			high:        day.TemperatureMax,
+			probability: day.PrecipitationProbability,
+		}
+	}
+
+	header := fmt.Sprintf("%s\nNext %d days:\n", locationHeader(location), len(forecast.Days))
+	return header + renderChart(columns, forecast.Units, true, width, style)
+}
+
+// renderChart draws the plots and the time axis. Daily charts draw each
+// column from low to high and are labelled with weekdays; hourly charts draw
+// bars from the bottom and are labelled with hours.
+func renderChart(columns []chartColumn, units api.Units, daily bool, width int, style Style) string {
+	if len(columns) == 0 {
+		return ""
+	}
+
+	maxCell := 3
+	if daily {
+		maxCell = 4
+	}
+	available := max(1, width-chartAxisWidth)
+	columns, merged := resample(columns, available)
+	cell := min(maxCell, max(1, available/len(columns)))
+
+	// Daily bars leave a space between days
+	bar, gap := cell, ""
+	if daily && cell > 1 {
+		bar, gap = cell-1, " "
+	}
+
+	lo, hi := columns[0].low, columns[0].high
+	for _, c := range columns {
+		lo = math.Min(lo, c.low)
+		hi = math.Max(hi, c.high)
+	}
+
+	var b strings.Builder
+	symbol := units.Temperature.Symbol()
+
+	// Temperature, in eighths of a cell above the bottom of the plot
+	levels := chartTempRows * 8
+	scale := func(v float64) int {
+		if hi == lo {
+			return levels / 2
+		}
+		return 1 + int(math.Round((v-lo)/(hi-lo)*float64(levels-1)))
+	}
+	for row := chartTempRows - 1; row >= 0; row-- {
+		label := ""
+		switch row {
+		case chartTempRows - 1:
+			label = fmt.Sprintf("%.0f%s", hi, symbol)
+		case 0:
+			label = fmt.Sprintf("%.0f%s", lo, symbol)
+		}
+
+		b.WriteString(padLeft(label, chartAxisWidth-2) + " │")
+		for _, c := range columns {
+			bottom := 0
+			if daily {
+				bottom = scale(c.low) - 1
+			}
+			text := chartCell(bottom, scale(c.high), row*8, bar)
+			if strings.TrimSpace(text) != "" {
+				text = style.Temperature(api.ConvertTemperature(c.high, units.Temperature, api.Celsius), text)
+			}
+			b.WriteString(text + gap)
+		}
+		b.WriteString("\n")
+	}
+
+	// Precipitation probability, in eighths of a cell
+	for row := chartRainRows - 1; row >= 0; row-- {
+		label := ""
+		if row == chartRainRows-1 {
+			label = "rain"
+		}
+
+		b.WriteString(padLeft(label, chartAxisWidth-2) + " │")
+		for _, c := range columns {
+			top := int(math.Round(float64(c.probability) / 100 * chartRainRows * 8))
+			if c.probability > 0 {
+				top = max(1, top)
+			}
+			text := chartCell(0, top, row*8, bar)
+			if strings.TrimSpace(text) != "" {
+				text = style.Rain(text)
+			}
+			b.WriteString(text + gap)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Repeat(" ", chartAxisWidth) + chartAxis(columns, cell, merged, daily) + "\n")
+
+	// Cells are padded with spaces; trailing ones would only add noise
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// chartCell draws the part of a bar spanning eighths [bottom, top) that falls
+// in the row starting at eighth base, width characters wide
+func chartCell(bottom, top, base, width int) string {
+	lo, hi := max(bottom, base), min(top, base+8)
+
+	var glyph string
+	switch {
+	case hi <= lo:
+		glyph = " "
+	case lo == base:
+		glyph = lowerBlocks[hi-base]
+	case hi == base+8 && hi-lo >= 4:
+		glyph = "▀"
+	case hi == base+8:
+		glyph = "▔"
+	default:
+		glyph = "━"
+	}
+	return strings.Repeat(glyph, width)
+}
+
+// chartAxis labels the columns with hours or weekdays where the labels fit
+func chartAxis(columns []chartColumn, cell, merged int, daily bool) string {
+	// Hourly labels are spaced evenly, with at least two spaces between them
+	step := hourLabelSteps[len(hourLabelSteps)-1]
+	for _, s := range hourLabelSteps {
+		if s*cell >= 4*merged {
+			step = s
+			break
+		}
+	}
+
+	var b strings.Builder
+	for i, c := range columns {
+		label := ""
+		if daily {
+			label = c.times[0].Format("Mon")
+		} else {
+			for _, t := range c.times {
+				if t.Minute() == 0 && t.Hour()%step == 0 {
+					label = t.Format("15")
+					break
+				}
+			}
+		}
+
+		// Skip labels that would run into the previous one
+		pos := i * cell
+		if label == "" || b.Len() > pos {
+			continue
+		}
+		b.WriteString(strings.Repeat(" ", pos-b.Len()) + label)
+	}
+	return b.String()
+}
+
+// resample merges neighbouring columns until at most n remain and returns
+// how many were merged into each. Merged columns span the temperatures and
+// keep the highest precipitation probability.
+func resample(columns []chartColumn, n int) ([]chartColumn, int) {
+	if len(columns) <= n {
+		return columns, 1
+	}
+
+	size := (len(columns) + n - 1) / n
+	merged := make([]chartColumn, 0, n)
+	for start := 0; start < len(columns); start += size {
+		group := columns[start:min(start+size, len(columns))]
+		c := chartColumn{low: group[0].low, high: group[0].high}
+		for _, g := range group {
+			c.times = append(c.times, g.times...)
+			c.low = math.Min(c.low, g.low)
+			c.high = math.Max(c.high, g.high)
+			c.probability = max(c.probability, g.probability)
+		}
+		merged = append(merged, c)
+	}
+	return merged, size
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func testHourlyForecast(hours int) *api.HourlyForecast {
+	zone := time.FixedZone("JST", 9*60*60)
+	forecast := &api.HourlyForecast{}
+	for i := range hours {
+		forecast.Hours = append(forecast.Hours, api.HourlyWeather{
+			Time:                     time.Date(2025, 3, 1, 21, 0, 0, 0, zone).Add(time.Duration(i) * time.Hour),
+			Temperature:              float64(i % 12),
+			PrecipitationProbability: i * 10 % 110,
+		})
+	}
+	return forecast
+}
+
+func TestFormatHourlyChart_Typical(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+
+	output := FormatHourlyChart(location, testHourlyForecast(6), 80, Style{})
+
+	assert.Equal(t, "Tokyo, JP\n"+
+		"Next 6 hours:\n"+
+		"    5°C │            ▃▃▃███\n"+
+		"        │      ▂▂▂▇▇▇██████\n"+
+		"    0°C │▁▁▁▆▆▆████████████\n"+
+		"   rain │\n"+
+		"        │   ▂▂▂▃▃▃▅▅▅▆▆▆███\n"+
+		"         21       00\n", output)
+}
+
+func TestFormatHourlyChart_FitsWidth(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+
+	for _, width := range []int{20, 40, 60, 100} {
+		output := FormatHourlyChart(location, testHourlyForecast(48), width, Style{})
+
+		for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+			assert.LessOrEqual(t, utf8.RuneCountInString(line), width, "width %d: %q", width, line)
+		}
+	}
+}
+
+func TestFormatHourlyChart_MergesHours(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+
+	output := FormatHourlyChart(location, testHourlyForecast(48), 33, Style{})
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+
+	// 48 hours in 24 columns: two hours per column, labelled every twelve hours
+	assert.Equal(t, "Next 48 hours:", lines[1])
+	assert.Equal(t, 9+24, utf8.RuneCountInString(lines[6]))
+	assert.Equal(t, "          00    12    00    12", lines[7])
+}
+
+func TestFormatHourlyChart_Empty(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+
+	assert.Equal(t, "Tokyo, JP\nNext 0 hours:\n", FormatHourlyChart(location, &api.HourlyForecast{}, 80, Style{}))
+}
+
+func TestFormatHourlyChart_Color(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	forecast := testHourlyForecast(24)
+
+	output := FormatHourlyChart(location, forecast, 80, Style{Mode: Color256})
+	plain := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(output, "")
+
+	assert.Contains(t, output, "\x1b[38;5;")
+	assert.Equal(t, FormatHourlyChart(location, forecast, 80, Style{}), plain)
+}
+
+func TestFormatDailyChart_Typical(t *testing.T) {
+	location := &api.Location{Name: "Berlin", Country: "DE"}
+	forecast := &api.DailyForecast{Days: []api.DailyWeather{
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), TemperatureMin: -2, TemperatureMax: 4, PrecipitationProbability: 0},
+		{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), TemperatureMin: 3, TemperatureMax: 10, PrecipitationProbability: 50},
+		{Date: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), TemperatureMin: 6, TemperatureMax: 7, PrecipitationProbability: 100},
+	}}
+
+	output := FormatDailyChart(location, forecast, 80, Style{})
+
+	assert.Equal(t, "Berlin, DE\n"+
+		"Next 3 days:\n"+
+		"   10°C │    ███ ▂▂▂\n"+
+		"        │▅▅▅ ▀▀▀ ▔▔▔\n"+
+		"   -2°C │███\n"+
+		"   rain │        ███\n"+
+		"        │    ███ ███\n"+
+		"         Sat Sun Mon\n", output)
+}
+
+func TestChartCell(t *testing.T) {
+	tests := []struct {
+		name        string
+		bottom, top int
+		want        string
+	}{
+		{"Empty", 0, 0, "  "},
+		{"Below the row", 0, 8, "  "},
+		{"Partial from the bottom", 8, 11, "▃▃"},
+		{"Full", 0, 24, "██"},
+		{"Upper half", 12, 16, "▀▀"},
+		{"Upper eighth", 15, 24, "▔▔"},
+		{"Inside the row", 10, 13, "━━"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, chartCell(tt.bottom, tt.top, 8, 2))
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// ColorMode is the color depth used for ANSI output
+type ColorMode int
+
+const (
+	// NoColor prints plain text
+	NoColor ColorMode = iota
+	// Color16 uses the 16 basic terminal colors
+	Color16
+	// Color256 uses the xterm 256-color palette
+	Color256
+	// TrueColor uses 24-bit RGB colors
+	TrueColor
+)
+
+// Palette is a color scheme
+type Palette string
+
+const (
+	// DefaultPalette uses a rainbow temperature gradient
+	DefaultPalette Palette = "default"
+	// ColorblindPalette only uses hues that stay distinct with color vision deficiencies
+	ColorblindPalette Palette = "colorblind"
+)
+
+// Style selects how output is colored. The zero value prints plain text.
+type Style struct {
+	Mode    ColorMode
+	Palette Palette
+}
+
+// ParsePalette parses a palette name
+func ParsePalette(name string) (Palette, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "default":
+		return DefaultPalette, nil
+	case "colorblind", "cb":
+		return ColorblindPalette, nil
+	}
+	return "", fmt.Errorf("unknown palette %q (want default or colorblind)", name)
+}
+
+// DetectColorMode picks the color depth from the --color setting (auto, always
+// or never), whether stdout is a terminal, and the environment: NO_COLOR and
+// FORCE_COLOR, then COLORTERM and TERM for the depth.
+func DetectColorMode(setting string, terminal bool, getenv func(string) string) (ColorMode, error) {
+	switch strings.ToLower(setting) {
+	case "never":
+		return NoColor, nil
+	case "always":
+		return max(terminalDepth(getenv), Color16), nil
+	case "", "auto":
+	default:
+		return NoColor, fmt.Errorf("unknown color setting %q (want auto, always or never)", setting)
+	}
+
+	// FORCE_COLOR=0..3 sets the depth even when stdout is not a terminal
+	if force := getenv("FORCE_COLOR"); force != "" {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return NoColor, nil
+		case "2":
+			return Color256, nil
+		case "3":
+			return TrueColor, nil
+		default:
+			return max(terminalDepth(getenv), Color16), nil
+		}
+	}
+
+	if getenv("NO_COLOR") != "" || !terminal || getenv("TERM") == "dumb" {
+		return NoColor, nil
+	}
+	return terminalDepth(getenv), nil
+}
+
+// terminalDepth guesses the color depth of the terminal from COLORTERM and TERM
+func terminalDepth(getenv func(string) string) ColorMode {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(getenv("TERM"), "256color") {
+		return Color256
+	}
+	return Color16
+}
+
+// rgb is a 24-bit color
+type rgb struct {
+	r, g, b uint8
+}
+
+// colorStop anchors a gradient color at a temperature in °C
+type colorStop struct {
+	celsius float64
+	color   rgb
+}
+
+// temperatureGradients run from cold to hot
+var temperatureGradients = map[Palette][]colorStop{
+	DefaultPalette: {
+		{-20, rgb{0x8e, 0x6b, 0xd8}}, // violet
+		{-5, rgb{0x3a, 0x8e, 0xf0}},  // blue
+		{5, rgb{0x2e, 0xc4, 0xd6}},   // cyan
+		{15, rgb{0x5c, 0xc8, 0x5c}},  // green
+		{22, rgb{0xf2, 0xd1, 0x3d}},  // yellow
+		{30, rgb{0xff, 0x8c, 0x1a}},  // orange
+		{38, rgb{0xe8, 0x30, 0x30}},  // red
+	},
+	// Okabe-Ito hues: blue to orange avoids red-green contrasts
+	ColorblindPalette: {
+		{-20, rgb{0x00, 0x72, 0xb2}}, // blue
+		{0, rgb{0x56, 0xb4, 0xe9}},   // sky blue
+		{15, rgb{0xf0, 0xe4, 0x42}},  // yellow
+		{25, rgb{0xe6, 0x9f, 0x00}},  // orange
+		{35, rgb{0xd5, 0x5e, 0x00}},  // vermillion
+	},
+}
+
+// groupColors color condition lines by weather code category
+var groupColors = map[Palette]map[api.WeatherGroup]rgb{
+	DefaultPalette: {
+		api.GroupClear:   {0xf2, 0xd1, 0x3d},
+		api.GroupCloudy:  {0xb0, 0xb0, 0xb0},
+		api.GroupFog:     {0x90, 0x90, 0x90},
+		api.GroupRain:    {0x3a, 0x8e, 0xf0},
+		api.GroupSnow:    {0xe0, 0xf0, 0xff},
+		api.GroupThunder: {0xc0, 0x5c, 0xe0},
+	},
+	ColorblindPalette: {
+		api.GroupClear:   {0xe6, 0x9f, 0x00},
+		api.GroupCloudy:  {0xb0, 0xb0, 0xb0},
+		api.GroupFog:     {0x90, 0x90, 0x90},
+		api.GroupRain:    {0x00, 0x72, 0xb2},
+		api.GroupSnow:    {0x56, 0xb4, 0xe9},
+		api.GroupThunder: {0xcc, 0x79, 0xa7},
+	},
+}
+
+// aqiBandColors color AQI category levels 0 (best) to 5 (worst)
+var aqiBandColors = map[Palette][]rgb{
+	// green, yellow, orange, red, purple and maroon, as used by the EPA
+	DefaultPalette: {
+		{0x00, 0xe4, 0x00}, {0xff, 0xff, 0x00}, {0xff, 0x7e, 0x00},
+		{0xff, 0x00, 0x00}, {0x8f, 0x3f, 0x97}, {0x7e, 0x00, 0x23},
+	},
+	// A light-to-dark sequence readable by brightness alone
+	ColorblindPalette: {
+		{0x56, 0xb4, 0xe9}, {0xf0, 0xe4, 0x42}, {0xe6, 0x9f, 0x00},
+		{0xd5, 0x5e, 0x00}, {0xcc, 0x79, 0xa7}, {0x88, 0x22, 0x55},
+	},
+}
+
+// Temperature colors text by where celsius falls on the cold-to-hot gradient
+func (s Style) Temperature(celsius float64, text string) string {
+	if s.Mode == NoColor {
+		return text
+	}
+
+	stops := temperatureGradients[s.palette()]
+	if celsius <= stops[0].celsius {
+		return s.paint(stops[0].color, text)
+	}
+	for i := 1; i < len(stops); i++ {
+		if celsius <= stops[i].celsius {
+			lo, hi := stops[i-1], stops[i]
+			return s.paint(mix(lo.color, hi.color, (celsius-lo.celsius)/(hi.celsius-lo.celsius)), text)
+		}
+	}
+	return s.paint(stops[len(stops)-1].color, text)
+}
+
+// Condition colors text by the category of a weather code
+func (s Style) Condition(code int, text string) string {
+	color, ok := groupColors[s.palette()][api.WeatherCodeGroup(code)]
+	if !ok {
+		return text
+	}
+	return s.paint(color, text)
+}
+
+// Rain colors text like rain conditions
+func (s Style) Rain(text string) string {
+	return s.paint(groupColors[s.palette()][api.GroupRain], text)
+}
+
+// AQIBand colors text by an AQI category level
+func (s Style) AQIBand(level int, text string) string {
+	bands := aqiBandColors[s.palette()]
+	if level < 0 || level >= len(bands) {
+		return text
+	}
+	return s.paint(bands[level], text)
+}
+
+func (s Style) palette() Palette {
+	if s.Palette == ColorblindPalette {
+		return ColorblindPalette
+	}
+	return DefaultPalette
+}
+
+// paint wraps text in the escape codes for c at the style's color depth
+func (s Style) paint(c rgb, text string) string {
+	switch s.Mode {
+	case TrueColor:
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", c.r, c.g, c.b, text)
+	case Color256:
+		return "\x1b[38;5;" + strconv.Itoa(xterm256(c)) + "m" + text + "\x1b[0m"
+	case Color16:
+		return "\x1b[" + strconv.Itoa(ansi16(c)) + "m" + text + "\x1b[0m"
+	default:
+		return text
+	}
+}
+
+// mix blends a and b; t=0 gives a and t=1 gives b
+func mix(a, b rgb, t float64) rgb {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return rgb{lerp(a.r, b.r), lerp(a.g, b.g), lerp(a.b, b.b)}
+}
+
+// xterm256 returns the nearest color of the 6×6×6 xterm color cube
+func xterm256(c rgb) int {
+	levels := []int{0, 95, 135, 175, 215, 255}
+	nearest := func(v uint8) int {
+		best := 0
+		for i, level := range levels {
+			if abs(int(v)-level) < abs(int(v)-levels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	return 16 + 36*nearest(c.r) + 6*nearest(c.g) + nearest(c.b)
+}
+
+// ansi16Colors are the typical RGB values of the 16 basic foreground colors
+var ansi16Colors = []struct {
+	code  int
+	color rgb
+}{
+	{30, rgb{0, 0, 0}}, {31, rgb{205, 0, 0}}, {32, rgb{0, 205, 0}}, {33, rgb{205, 205, 0}},
+	{34, rgb{0, 0, 238}}, {35, rgb{205, 0, 205}}, {36, rgb{0, 205, 205}}, {37, rgb{229, 229, 229}},
+	{90, rgb{127, 127, 127}}, {91, rgb{255, 0, 0}}, {92, rgb{0, 255, 0}}, {93, rgb{255, 255, 0}},
+	{94, rgb{92, 92, 255}}, {95, rgb{255, 0, 255}}, {96, rgb{0, 255, 255}}, {97, rgb{255, 255, 255}},
+}
+
+// ansi16 returns the code of the basic color nearest to c
+func ansi16(c rgb) int {
+	best, bestDist := 0, -1
+	for _, candidate := range ansi16Colors {
+		dr := int(c.r) - int(candidate.color.r)
+		dg := int(c.g) - int(candidate.color.g)
+		db := int(c.b) - int(candidate.color.b)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = candidate.code, d
+		}
+	}
+	return best
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
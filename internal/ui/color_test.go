@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectColorMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		setting  string
+		terminal bool
+		env      map[string]string
+		want     ColorMode
+	}{
+		{"Terminal", "auto", true, nil, Color16},
+		{"Pipe", "auto", false, nil, NoColor},
+		{"Empty setting is auto", "", true, nil, Color16},
+		{"NO_COLOR", "auto", true, map[string]string{"NO_COLOR": "1"}, NoColor},
+		{"Dumb terminal", "auto", true, map[string]string{"TERM": "dumb"}, NoColor},
+		{"256 colors", "auto", true, map[string]string{"TERM": "xterm-256color"}, Color256},
+		{"Truecolor", "auto", true, map[string]string{"COLORTERM": "truecolor", "TERM": "xterm-256color"}, TrueColor},
+		{"24bit", "auto", true, map[string]string{"COLORTERM": "24bit"}, TrueColor},
+		{"FORCE_COLOR on a pipe", "auto", false, map[string]string{"FORCE_COLOR": "1"}, Color16},
+		{"FORCE_COLOR=2", "auto", false, map[string]string{"FORCE_COLOR": "2"}, Color256},
+		{"FORCE_COLOR=3", "auto", false, map[string]string{"FORCE_COLOR": "3"}, TrueColor},
+		{"FORCE_COLOR beats NO_COLOR", "auto", true, map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}, Color16},
+		{"FORCE_COLOR=0", "auto", true, map[string]string{"FORCE_COLOR": "0"}, NoColor},
+		{"Always on a pipe", "always", false, map[string]string{"NO_COLOR": "1"}, Color16},
+		{"Always keeps depth", "always", false, map[string]string{"COLORTERM": "truecolor"}, TrueColor},
+		{"Never", "never", true, map[string]string{"FORCE_COLOR": "3"}, NoColor},
+		{"Case insensitive", "NEVER", true, nil, NoColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+
+			mode, err := DetectColorMode(tt.setting, tt.terminal, getenv)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mode)
+		})
+	}
+}
+
+func TestDetectColorMode_Invalid(t *testing.T) {
+	_, err := DetectColorMode("sometimes", true, func(string) string { return "" })
+
+	assert.EqualError(t, err, `unknown color setting "sometimes" (want auto, always or never)`)
+}
+
+func TestParsePalette(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Palette
+		wantErr bool
+	}{
+		{"", DefaultPalette, false},
+		{"default", DefaultPalette, false},
+		{"Colorblind", ColorblindPalette, false},
+		{"cb", ColorblindPalette, false},
+		{"sepia", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			palette, err := ParsePalette(tt.name)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, palette)
+		})
+	}
+}
+
+func TestStyle_Temperature(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   Style
+		celsius float64
+		want    string
+	}{
+		{"Plain", Style{}, 21, "21°C"},
+		{"Below the scale", Style{Mode: TrueColor}, -40, "\x1b[38;2;142;107;216m21°C\x1b[0m"},
+		{"Above the scale", Style{Mode: TrueColor}, 45, "\x1b[38;2;232;48;48m21°C\x1b[0m"},
+		{"On a stop", Style{Mode: TrueColor}, 15, "\x1b[38;2;92;200;92m21°C\x1b[0m"},
+		{"Between stops", Style{Mode: TrueColor}, 0, "\x1b[38;2;52;169;227m21°C\x1b[0m"},
+		{"256 colors", Style{Mode: Color256}, 38, "\x1b[38;5;167m21°C\x1b[0m"},
+		{"16 colors", Style{Mode: Color16}, -5, "\x1b[94m21°C\x1b[0m"},
+		{"Colorblind", Style{Mode: TrueColor, Palette: ColorblindPalette}, 35, "\x1b[38;2;213;94;0m21°C\x1b[0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.style.Temperature(tt.celsius, "21°C"))
+		})
+	}
+}
+
+func TestStyle_Condition(t *testing.T) {
+	style := Style{Mode: TrueColor}
+
+	assert.Equal(t, "\x1b[38;2;58;142;240mRain\x1b[0m", style.Condition(63, "Rain"))
+	assert.Equal(t, "Unknown", style.Condition(42, "Unknown"))
+	assert.Equal(t, "Rain", Style{}.Condition(63, "Rain"))
+}
+
+func TestStyle_AQIBand(t *testing.T) {
+	style := Style{Mode: TrueColor, Palette: ColorblindPalette}
+
+	assert.Equal(t, "\x1b[38;2;86;180;233m(Good)\x1b[0m", style.AQIBand(0, "(Good)"))
+	assert.Equal(t, "(Unknown)", style.AQIBand(6, "(Unknown)"))
+	assert.Equal(t, "(Unknown)", style.AQIBand(-1, "(Unknown)"))
+}
+
+func TestXterm256(t *testing.T) {
+	assert.Equal(t, 16, xterm256(rgb{0, 0, 0}))
+	assert.Equal(t, 231, xterm256(rgb{255, 255, 255}))
+	assert.Equal(t, 196, xterm256(rgb{255, 0, 0}))
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// FormatDailyForecast formats a day-by-day forecast as a table with one row per day
+func FormatDailyForecast(location *api.Location, forecast *api.DailyForecast) string {
+	return FormatDailyForecastStyled(location, forecast, Style{})
+}
+
+// FormatDailyForecastStyled formats a day-by-day forecast table in the colors of style
+func FormatDailyForecastStyled(location *api.Location, forecast *api.DailyForecast, style Style) string {
+	var b strings.Builder
+	units := forecast.Units
+
+	fmt.Fprintf(&b, "%s\n", locationHeader(location))
+	fmt.Fprintf(&b, "Next %d days:\n", len(forecast.Days))
+	fmt.Fprintf(&b, "%-9s  %7s  %7s  %8s  %4s  %10s  %-11s  %s\n",
+		"Day", "High", "Low", "Precip", "Prob", "Wind", "Sun", "Conditions")
+
+	for _, day := range forecast.Days {
+		fmt.Fprintf(&b, "%s  %s  %s  %s  %3d%%  %s  %s-%s  %s %s\n",
+			day.Date.Format("Mon 01-02"),
+			styleTemperature(padLeft(formatTemperature(day.TemperatureMax, units), 7), day.TemperatureMax, units, style),
+			styleTemperature(padLeft(formatTemperature(day.TemperatureMin, units), 7), day.TemperatureMin, units, style),
+			padLeft(formatPrecipitation(day.PrecipitationSum, units), 8),
+			day.PrecipitationProbability,
+			padLeft(formatWindSpeed(day.WindSpeedMax, units), 10),
+			formatClock(day.Sunrise),
+			formatClock(day.Sunset),
+			style.Condition(day.WeatherCode, day.WeatherCodeDesc),
+			api.WeatherCodeEmoji(day.WeatherCode),
+		)
+	}
+
+	return b.String()
+}
+
+// formatClock formats a local time of day, using dashes for missing times
+func formatClock(t time.Time) string {
+	if t.IsZero() {
+		return "--:--"
+	}
+	return t.Format("15:04")
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDailyForecast_Typical(t *testing.T) {
+	location := &api.Location{Name: "Berlin", Country: "DE"}
+	zone := time.FixedZone("CET", 60*60)
+
+	forecast := &api.DailyForecast{Days: []api.DailyWeather{
+		{
+			Date:                     time.Date(2025, 3, 1, 0, 0, 0, 0, zone),
+			TemperatureMax:           12.3,
+			TemperatureMin:           4.1,
+			PrecipitationSum:         0,
+			PrecipitationProbability: 10,
+			WeatherCode:              2,
+			WeatherCodeDesc:          "Partly cloudy",
+			Sunrise:                  time.Date(2025, 3, 1, 6, 58, 0, 0, zone),
+			Sunset:                   time.Date(2025, 3, 1, 17, 53, 0, 0, zone),
+			WindSpeedMax:             18.2,
+		},
+		{
+			Date:                     time.Date(2025, 3, 2, 0, 0, 0, 0, zone),
+			TemperatureMax:           -9.8,
+			TemperatureMin:           -21.5,
+			PrecipitationSum:         16.4,
+			PrecipitationProbability: 100,
+			WeatherCode:              75,
+			WeatherCodeDesc:          "Heavy snow",
+			WindSpeedMax:             131.0,
+		},
+	}}
+
+	output := FormatDailyForecast(location, forecast)
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "Berlin, DE", lines[0])
+	assert.Equal(t, "Next 2 days:", lines[1])
+	assert.Equal(t, "Sat 03-01   12.3°C    4.1°C    0.0 mm   10%   18.2 km/h  06:58-17:53  Partly cloudy ⛅", lines[3])
+	assert.Equal(t, "Sun 03-02   -9.8°C  -21.5°C   16.4 mm  100%  131.0 km/h  --:-----:--  Heavy snow ❄️", lines[4])
+
+	// Conditions line up with the header
+	column := strings.Index(lines[2], "Conditions")
+	for _, line := range lines[3:] {
+		prefix := line[:strings.LastIndex(line, "  ")+2]
+		assert.Equal(t, column, utf8.RuneCountInString(prefix))
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	assert.Equal(t, "--:--", formatClock(time.Time{}))
+	assert.Equal(t, "07:05", formatClock(time.Date(2025, 1, 1, 7, 5, 0, 0, time.UTC)))
+}
+
+func TestFormatDailyForecast_Imperial(t *testing.T) {
+	location := &api.Location{Name: "Chicago", Country: "US"}
+	forecast := &api.DailyForecast{
+		Units: api.ImperialUnits,
+		Days: []api.DailyWeather{{
+			Date:             time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			TemperatureMax:   48.2,
+			TemperatureMin:   31.0,
+			PrecipitationSum: 0.25,
+			WindSpeedMax:     15.5,
+			WeatherCodeDesc:  "Clear",
+		}},
+	}
+
+	output := FormatDailyForecast(location, forecast)
+
+	assert.Contains(t, output, "Sat 03-01   48.2°F   31.0°F   0.25 in    0%    15.5 mph")
+}
@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+const (
+	// dashboardSidebarMin is the narrowest screen that shows the sidebar
+	dashboardSidebarMin = 60
+
+	// dashboardSideBySide is the narrowest main pane that puts the daily
+	// table next to the current conditions instead of under the chart
+	dashboardSideBySide = 120
+
+	// dashboardCurrentWidth is the width of the current conditions beside the daily table
+	dashboardCurrentWidth = 32
+)
+
+// Dashboard is what the full-screen dashboard shows
+type Dashboard struct {
+	// Places are the labels listed in the sidebar; Selected is the one shown
+	Places   []string
+	Selected int
+
+	// Location is the selected place once it is found; Weather, Hourly and
+	// Daily are its last good readings, nil until they arrive
+	Location *api.Location
+	Weather  *api.Weather
+	Hourly   *api.HourlyForecast
+	Daily    *api.DailyForecast
+
+	// Updated is when the readings were fetched, zero while loading
+	Updated time.Time
+
+	// Err is the failure of the latest update, shown above the readings
+	// until an update succeeds; Failed is when it happened and Retry the
+	// wait before the next attempt
+	Err    error
+	Failed time.Time
+	Retry  time.Duration
+
+	// Interval is the time between automatic updates
+	Interval time.Duration
+}
+
+// FormatDashboard lays out d on a screen of width by height cells and
+// returns exactly height lines of at most width columns. The sidebar is
+// hidden on narrow screens, and wide ones put the daily table beside the
+// current conditions.
+func FormatDashboard(d *Dashboard, now time.Time, width, height int, style Style) []string {
+	lines := make([]string, height)
+	if height < 3 || width < 20 {
+		for i := range lines {
+			lines[i] = fitLine("", width)
+		}
+		if height > 0 {
+			lines[0] = fitLine("Terminal too small", width)
+		}
+		return lines
+	}
+
+	title := "sky"
+	if d.Selected >= 0 && d.Selected < len(d.Places) {
+		title += " · " + d.Places[d.Selected]
+	}
+	status := "Loading"
+	if !d.Updated.IsZero() {
+		status = "Updated " + FormatAge(now.Sub(d.Updated)) + " · every " + FormatDuration(d.Interval)
+	}
+	lines[0] = spread(" "+title, status+" ", width, style)
+	lines[height-1] = fitLine(" ↑/↓ select · r refresh · q quit", width)
+
+	body := height - 2
+	sidebar := 0
+	if width >= dashboardSidebarMin && len(d.Places) > 1 {
+		sidebar = sidebarWidth(d.Places, width)
+	}
+	paneWidth := width - sidebar
+	if sidebar > 0 {
+		paneWidth--
+	}
+
+	side := dashboardSidebar(d, sidebar, body, style)
+	pane := dashboardPane(d, paneWidth, style)
+	for i := 0; i < body; i++ {
+		text := ""
+		if i < len(pane) {
+			text = pane[i]
+		}
+		line := fitLine(text, paneWidth)
+		if sidebar > 0 {
+			line = side[i] + "│" + line
+		}
+		lines[i+1] = line
+	}
+	return lines
+}
+
+// sidebarWidth returns the width of the sidebar: its longest label with a
+// margin, between a sixth and a quarter of the screen
+func sidebarWidth(places []string, width int) int {
+	longest := len("Places")
+	for _, p := range places {
+		longest = max(longest, DisplayWidth(p))
+	}
+	return min(max(longest+4, width/6), width/4)
+}
+
+// dashboardSidebar returns the height lines of the sidebar, marking the selected place
+func dashboardSidebar(d *Dashboard, width, height int, style Style) []string {
+	lines := make([]string, height)
+	if width == 0 {
+		return lines
+	}
+
+	// Keep the selected place in view when the list is longer than the screen
+	first := max(0, d.Selected-(height-3))
+	lines[0] = fitLine(" Places", width)
+	for i := 1; i < height; i++ {
+		index := first + i - 2
+		switch {
+		case i == 1 || index >= len(d.Places):
+			lines[i] = fitLine("", width)
+		case index == d.Selected:
+			lines[i] = highlight(fitLine(" ▸ "+d.Places[index], width), style)
+		default:
+			lines[i] = fitLine("   "+d.Places[index], width)
+		}
+	}
+	return lines
+}
+
+// dashboardPane returns the lines of the main pane: the failure banner,
+// the current conditions, the hourly chart and the daily table
+func dashboardPane(d *Dashboard, width int, style Style) []string {
+	var lines []string
+	if d.Err != nil {
+		lines = append(lines, "", " "+strings.TrimSuffix(FormatWatchBanner(d.Err, d.Failed, d.Retry), "\n"))
+	}
+	if d.Weather == nil || d.Location == nil {
+		if d.Err == nil {
+			lines = append(lines, "", " Loading…")
+		}
+		return lines
+	}
+
+	current := splitLines(FormatWeatherStyled(d.Location, d.Weather, style))
+	var daily, chart []string
+	if d.Daily != nil {
+		daily = splitLines(FormatDailyForecastStyled(d.Location, d.Daily, style))[1:]
+	}
+	if d.Hourly != nil {
+		// Leave room for the margin on the left
+		chart = splitLines(FormatHourlyChart(d.Location, d.Hourly, width-1, style))[1:]
+	}
+
+	lines = append(lines, "")
+	if width >= dashboardSideBySide && daily != nil {
+		for i := range max(len(current), len(daily)) {
+			left, right := "", ""
+			if i < len(current) {
+				left = current[i]
+			}
+			if i < len(daily) {
+				right = daily[i]
+			}
+			lines = append(lines, " "+fitLine(left, dashboardCurrentWidth)+right)
+		}
+		return append(lines, indent(append([]string{""}, chart...))...)
+	}
+
+	lines = append(lines, indent(current)...)
+	if chart != nil {
+		lines = append(lines, indent(append([]string{""}, chart...))...)
+	}
+	if daily != nil {
+		lines = append(lines, indent(append([]string{""}, daily...))...)
+	}
+	return lines
+}
+
+// indent returns lines with a one-column margin
+func indent(lines []string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if line != "" {
+			line = " " + line
+		}
+		result[i] = line
+	}
+	return result
+}
+
+// splitLines splits formatted output into its lines
+func splitLines(s string) []string {
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// spread places left and right at the edges of a line of the given width,
+// highlighted as a title bar
+func spread(left, right string, width int, style Style) string {
+	gap := width - DisplayWidth(left) - DisplayWidth(right)
+	if gap < 1 {
+		return highlight(fitLine(left, width), style)
+	}
+	return highlight(left+strings.Repeat(" ", gap)+right, style)
+}
+
+// highlight shows text in reverse video, unless colors are off
+func highlight(text string, style Style) string {
+	if style.Mode == NoColor {
+		return text
+	}
+	return "\x1b[7m" + text + "\x1b[0m"
+}
+
+// fitLine cuts or pads s to exactly width columns. Escape codes do not
+// count toward the width, and colors cut short are reset.
+func fitLine(s string, width int) string {
+	var b strings.Builder
+	used, escaped, cut := 0, false, false
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			escaped = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r, s[i+size:])
+		if used+w > width {
+			cut = true
+			break
+		}
+		b.WriteString(s[i : i+size])
+		used += w
+		i += size
+	}
+	if cut && escaped {
+		b.WriteString("\x1b[0m")
+	}
+	b.WriteString(strings.Repeat(" ", max(0, width-used)))
+	return b.String()
+}
+
+// DisplayWidth returns the number of terminal columns s takes up, leaving
+// out escape codes and counting emoji and East Asian wide characters as two
+func DisplayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r, s[i+size:])
+		i += size
+	}
+	return width
+}
+
+// escapeLength returns the length of the CSI escape sequence s starts with, or 0
+func escapeLength(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// runeWidth returns the columns r takes up; rest is the text after it,
+// where a variation selector asks for emoji presentation
+func runeWidth(r rune, rest string) int {
+	switch {
+	case r == 0xfe0f, r == 0x200d, r >= 0xfe00 && r <= 0xfe0e,
+		r >= 0x0300 && r <= 0x036f, r >= 0x200b && r <= 0x200f, r >= 0x20d0 && r <= 0x20ff:
+		return 0
+	case wideRune(r), strings.HasPrefix(rest, "\ufe0f"):
+		return 2
+	}
+	return 1
+}
+
+// wideRanges are the code points shown two columns wide: East Asian wide
+// and fullwidth characters and emoji with emoji presentation
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x23e9, 0x23ec}, {0x23f0, 0x23f0}, {0x23f3, 0x23f3},
+	{0x25fd, 0x25fe}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693},
+	{0x26a1, 0x26a1}, {0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5}, {0x26fa, 0x26fa},
+	{0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b}, {0x2728, 0x2728}, {0x274c, 0x274c},
+	{0x274e, 0x274e}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0},
+	{0x27bf, 0x27bf}, {0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf}, {0xac00, 0xd7a3},
+	{0xf900, 0xfaff}, {0xfe30, 0xfe4f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff}, {0x1f900, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x3fffd},
+}
+
+// wideRune reports whether r is shown two columns wide
+func wideRune(r rune) bool {
+	for _, w := range wideRanges {
+		if r >= w[0] && r <= w[1] {
+			return true
+		}
+	}
+	return false
+}
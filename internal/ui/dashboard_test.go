@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// dashboardTest returns a dashboard of two places with readings fetched 3 minutes before now
+func dashboardTest() (*Dashboard, time.Time) {
+	now := time.Date(2025, 3, 1, 14, 5, 0, 0, time.UTC)
+	d := &Dashboard{
+		Places:   []string{"Tokyo", "@home"},
+		Location: &api.Location{Name: "Tokyo", Country: "JP"},
+		Weather:  &api.Weather{Temperature: 12.5, ApparentTemp: 11, WeatherCode: 0, WeatherCodeDesc: "Clear sky"},
+		Hourly:   &api.HourlyForecast{},
+		Daily:    &api.DailyForecast{},
+		Updated:  now.Add(-3 * time.Minute),
+		Interval: 10 * time.Minute,
+	}
+	for i := range 24 {
+		d.Hourly.Hours = append(d.Hourly.Hours, api.HourlyWeather{
+			Time:        time.Date(2025, 3, 1, i, 0, 0, 0, time.UTC),
+			Temperature: float64(i),
+		})
+	}
+	for i := range 7 {
+		d.Daily.Days = append(d.Daily.Days, api.DailyWeather{
+			Date:            time.Date(2025, 3, 1+i, 0, 0, 0, 0, time.UTC),
+			TemperatureMax:  float64(10 + i),
+			WeatherCodeDesc: "Clear",
+		})
+	}
+	return d, now
+}
+
+func TestFormatDashboard(t *testing.T) {
+	d, now := dashboardTest()
+
+	lines := FormatDashboard(d, now, 100, 40, Style{Mode: NoColor})
+
+	assert.Len(t, lines, 40)
+	for _, line := range lines {
+		assert.Equal(t, 100, DisplayWidth(line), line)
+	}
+	assert.True(t, strings.HasPrefix(lines[0], " sky · Tokyo "))
+	assert.True(t, strings.HasSuffix(lines[0], " Updated 3 min ago · every 10m "))
+	assert.True(t, strings.HasPrefix(lines[39], " ↑/↓ select · r refresh · q quit"))
+
+	// The sidebar marks the selected place; the pane shows the current
+	// conditions, then the chart, then the daily table
+	assert.Equal(t, " Places         │", lines[1][:len(" Places         │")])
+	assert.True(t, strings.HasPrefix(lines[2], "                │ Tokyo, JP"))
+	assert.True(t, strings.HasPrefix(lines[3], " ▸ Tokyo        │ Clear sky"))
+	assert.True(t, strings.HasPrefix(lines[4], "   @home        │ Temp: 12.5°C"))
+	text := strings.Join(lines, "\n")
+	assert.Less(t, strings.Index(text, "Temp: 12.5°C"), strings.Index(text, "Next 24 hours:"))
+	assert.Less(t, strings.Index(text, "Next 24 hours:"), strings.Index(text, "Next 7 days:"))
+	assert.Equal(t, 1, strings.Count(text, "Tokyo, JP"))
+}
+
+func TestFormatDashboard_Layouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		height   int
+		sidebar  bool
+		together bool
+	}{
+		{"Narrow", 50, 30, false, false},
+		{"Medium", 100, 30, true, false},
+		{"Wide", 160, 30, true, true},
+		{"Short", 100, 10, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, now := dashboardTest()
+
+			lines := FormatDashboard(d, now, tt.width, tt.height, Style{Mode: NoColor})
+
+			assert.Len(t, lines, tt.height)
+			for _, line := range lines {
+				assert.Equal(t, tt.width, DisplayWidth(line), line)
+			}
+			text := strings.Join(lines, "\n")
+			assert.Equal(t, tt.sidebar, strings.Contains(text, "▸ Tokyo"))
+
+			// Wide screens put the daily table beside the current conditions
+			together := false
+			for _, line := range lines {
+				together = together || strings.Contains(line, "Temp: 12.5°C") && strings.Contains(line, "Sat 03-01")
+			}
+			assert.Equal(t, tt.together, together)
+		})
+	}
+}
+
+func TestFormatDashboard_States(t *testing.T) {
+	_, now := dashboardTest()
+	failed := now.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		d      *Dashboard
+		width  int
+		height int
+		want   []string
+	}{
+		{"Loading", &Dashboard{Places: []string{"Tokyo"}, Interval: time.Minute}, 80, 20,
+			[]string{" sky · Tokyo", "Loading ", " Loading…"}},
+		{"Failed", &Dashboard{Places: []string{"Tokyo"}, Err: errors.New("connection refused"), Failed: failed, Retry: time.Minute}, 80, 20,
+			[]string{" ⚠ Update failed at 14:04, retrying in 1m: connection refused"}},
+		{"Too small", &Dashboard{}, 30, 2, []string{"Terminal too small"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := FormatDashboard(tt.d, now, tt.width, tt.height, Style{Mode: NoColor})
+
+			assert.Len(t, lines, tt.height)
+			for _, want := range tt.want {
+				assert.Contains(t, strings.Join(lines, "\n"), want)
+			}
+		})
+	}
+}
+
+func TestFormatDashboard_Color(t *testing.T) {
+	d, now := dashboardTest()
+
+	lines := FormatDashboard(d, now, 100, 40, Style{Mode: Color16})
+
+	// The title bar and the selected place are in reverse video
+	assert.True(t, strings.HasPrefix(lines[0], "\x1b[7m sky · Tokyo"))
+	assert.True(t, strings.HasPrefix(lines[3], "\x1b[7m ▸ Tokyo"))
+	for _, line := range lines {
+		assert.Equal(t, 100, DisplayWidth(line), line)
+	}
+}
+
+func TestFitLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"Padded", "abc", 5, "abc  "},
+		{"Cut", "abcdef", 4, "abcd"},
+		{"Wide characters", "東京都", 5, "東京 "},
+		{"Colors", "\x1b[33mwarm\x1b[0m", 6, "\x1b[33mwarm\x1b[0m  "},
+		{"Colors cut", "\x1b[33mwarm\x1b[0m", 2, "\x1b[33mwa\x1b[0m"},
+		{"Emoji", "a☀️b", 3, "a☀️"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fitLine(tt.s, tt.width))
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"Tokyo", 5},
+		{"Zürich", 6},
+		{"東京", 4},
+		{"⛅", 2},
+		{"☀️", 2},
+		{"\x1b[33m21.3°C\x1b[0m", 6},
+		{"│▁█", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.want, DisplayWidth(tt.s))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/cache"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+)
+
+// errorHint suggests how to recover from err, or returns "" when there is nothing to add
+func errorHint(err error) string {
+	var apiErr *api.APIError
+	var netErr *api.NetworkError
+	var respErr *api.ResponseError
+
+	switch {
+	case errors.Is(err, favorites.ErrNotFound):
+		return "run `sky fav list` to see the saved places"
+	case errors.Is(err, api.ErrLocationNotFound):
+		return "check the spelling, or run `sky search <name>` to list matching places"
+	case errors.Is(err, cache.ErrMiss):
+		return "run once with --cache while online to fill the cache"
+	case errors.Is(err, api.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "the weather service did not answer in time; try again later"
+	case errors.As(err, &netErr):
+		return "check your internet connection, or the SKY_*_URL settings if you use a self-hosted server"
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return "too many requests; wait a minute before trying again"
+		case apiErr.StatusCode >= 500:
+			return "the weather service is having problems; try again later"
+		case apiErr.StatusCode == http.StatusNotFound:
+			return "check that the SKY_*_URL settings point at an Open-Meteo compatible server"
+		}
+	case errors.As(err, &respErr):
+		return "the service sent unexpected data; check that the SKY_*_URL settings point at an Open-Meteo compatible server"
+	}
+	return ""
+}
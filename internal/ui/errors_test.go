@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/cache"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+)
+
+func TestFormatError_Hints(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		hint string
+	}{
+		{"Location not found", api.ErrLocationNotFound, "sky search"},
+		{"Wrapped not found", fmt.Errorf("geocoding Atlantis: %w", api.ErrLocationNotFound), "sky search"},
+		{"Timeout", &api.NetworkError{What: "weather", Err: context.DeadlineExceeded}, "did not answer in time"},
+		{"Deadline", context.DeadlineExceeded, "did not answer in time"},
+		{"Network", &api.NetworkError{What: "weather", Err: errors.New("connection refused")}, "internet connection"},
+		{"Rate limited", &api.APIError{StatusCode: 429}, "too many requests"},
+		{"Server error", &api.APIError{StatusCode: 503}, "try again later"},
+		{"Wrong endpoint", &api.APIError{StatusCode: 404}, "SKY_*_URL"},
+		{"Unknown favorite", fmt.Errorf("%w @home", favorites.ErrNotFound), "sky fav list"},
+		{"Offline cache miss", fmt.Errorf("%w for \"Berlin\" (offline)", cache.ErrMiss), "--cache"},
+		{"Invalid response", &api.ResponseError{Err: errors.New("unexpected EOF")}, "unexpected data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := FormatError(tt.err)
+
+			assert.Contains(t, output, "Error: "+tt.err.Error()+"\n")
+			assert.Contains(t, output, "\nHint: ")
+			assert.Contains(t, output, tt.hint)
+		})
+	}
+}
+
+func TestFormatError_NoHint(t *testing.T) {
+	// Bad requests carry their own explanation in the reason
+	err := &api.APIError{StatusCode: 400, Reason: "Latitude must be in range of -90 to 90°."}
+
+	assert.Equal(t, "Error: API returned status 400: Latitude must be in range of -90 to 90°.\n", FormatError(err))
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+)
+
+// FormatFavorites lists saved favorites with their aliases, places and coordinates
+func FormatFavorites(list []favorites.Favorite) string {
+	width := 0
+	for _, f := range list {
+		width = max(width, len(favorites.Prefix+f.Alias))
+	}
+
+	var b strings.Builder
+	for _, f := range list {
+		fmt.Fprintf(&b, "%-*s  %s\n", width, favorites.Prefix+f.Alias, LocationLabel(&f.Location))
+		fmt.Fprintf(&b, "%*s  %s\n", width, "", locationDetails(&f.Location))
+	}
+	return b.String()
+}
+
+// locationDetails returns the coordinates and timezone of a location
+func locationDetails(location *api.Location) string {
+	details := []string{fmt.Sprintf("%.4f, %.4f", location.Latitude, location.Longitude)}
+	if location.Timezone != "" {
+		details = append(details, location.Timezone)
+	}
+	return strings.Join(details, " · ")
+}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/kakkoiirus/sky-cli/internal/favorites"
+)
+
+func TestFormatFavorites(t *testing.T) {
+	list := []favorites.Favorite{
+		{Alias: "home", Location: api.Location{Name: "Berlin", Admin1: "Land Berlin", Country: "DE", Latitude: 52.52437, Longitude: 13.41053, Timezone: "Europe/Berlin"}},
+		{Alias: "cabin", Location: api.Location{Name: "46.5000°N 8.1000°E", Latitude: 46.5, Longitude: 8.1}},
+	}
+
+	expected := "@home   Berlin, Land Berlin, DE\n" +
+		"        52.5244, 13.4105 · Europe/Berlin\n" +
+		"@cabin  46.5000°N 8.1000°E\n" +
+		"        46.5000, 8.1000\n"
+	assert.Equal(t, expected, FormatFavorites(list))
+}
+
+func TestFormatFavorites_Empty(t *testing.T) {
+	assert.Equal(t, "", FormatFavorites(nil))
+}
@@ -2,25 +2,180 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/kakkoiirus/sky-cli/internal/api"
 )
 
 // FormatWeather formats the weather data for display
 func FormatWeather(location *api.Location, weather *api.Weather) string {
+	return FormatWeatherStyled(location, weather, Style{})
+}
+
+// FormatWeatherStyled formats the weather data for display in the colors of style
+func FormatWeatherStyled(location *api.Location, weather *api.Weather, style Style) string {
+	return formatCurrent(location, weather, style) + staleMarker(weather)
+}
+
+// formatCurrent formats the summary lines shared by the weather views
+func formatCurrent(location *api.Location, weather *api.Weather, style Style) string {
 	emoji := api.WeatherCodeEmoji(weather.WeatherCode)
 
-	return fmt.Sprintf("%s, %s\n%s %s\nTemp: %.1f°C\nFeels like: %.1f°C\n",
-		location.Name,
-		location.Country,
-		weather.WeatherCodeDesc,
+	return fmt.Sprintf("%s\n%s %s\nTemp: %s\nFeels like: %s\n",
+		locationHeader(location),
+		style.Condition(weather.WeatherCode, weather.WeatherCodeDesc),
 		emoji,
-		weather.Temperature,
-		weather.ApparentTemp,
+		styleTemperature(formatTemperature(weather.Temperature, weather.Units), weather.Temperature, weather.Units, style),
+		styleTemperature(formatTemperature(weather.ApparentTemp, weather.Units), weather.ApparentTemp, weather.Units, style),
+	)
+}
+
+// FormatWeatherDetail formats the weather data with all available current conditions
+func FormatWeatherDetail(location *api.Location, weather *api.Weather) string {
+	return FormatWeatherDetailStyled(location, weather, Style{})
+}
+
+// FormatWeatherDetailStyled formats all available current conditions in the colors of style
+func FormatWeatherDetailStyled(location *api.Location, weather *api.Weather, style Style) string {
+	return formatCurrent(location, weather, style) + formatDetail(weather, style) + staleMarker(weather)
+}
+
+// formatDetail formats the conditions FormatWeatherDetail adds to the summary
+func formatDetail(weather *api.Weather, style Style) string {
+	var b strings.Builder
+	units := weather.Units
+
+	fmt.Fprintf(&b, "Humidity: %d%%\n", weather.Humidity)
+	fmt.Fprintf(&b, "Dew point: %s\n", styleTemperature(formatTemperature(weather.DewPoint, units), weather.DewPoint, units, style))
+	fmt.Fprintf(&b, "Wind: %s %s (gusts %s)\n",
+		formatWindSpeed(weather.WindSpeed, units),
+		CompassDirection(weather.WindDirection),
+		formatWindSpeed(weather.WindGusts, units),
 	)
+	fmt.Fprintf(&b, "Pressure: %.1f hPa\n", weather.Pressure)
+	fmt.Fprintf(&b, "Precipitation: %s\n", formatPrecipitation(weather.Precipitation, units))
+	fmt.Fprintf(&b, "Cloud cover: %d%%\n", weather.CloudCover)
+	fmt.Fprintf(&b, "UV index: %.1f (%s)\n", weather.UVIndex, UVIndexCategory(weather.UVIndex))
+	fmt.Fprintf(&b, "Visibility: %s\n", formatVisibility(weather.VisibilityUnit.Meters(weather.Visibility), units))
+	if weather.IsDay {
+		b.WriteString("Daylight: yes\n")
+	} else {
+		b.WriteString("Daylight: no\n")
+	}
+
+	return b.String()
+}
+
+// staleMarker returns the line telling how old cached conditions are,
+// or "" for fresh conditions
+func staleMarker(weather *api.Weather) string {
+	if !weather.Stale {
+		return ""
+	}
+	if weather.FetchedAt.IsZero() {
+		return "(cached)\n"
+	}
+	return fmt.Sprintf("(cached, updated %s)\n", FormatAge(time.Since(weather.FetchedAt)))
+}
+
+// FormatAge describes how long ago something happened, e.g. "5 min ago"
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min ago", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%d h ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%d days ago", int(d/(24*time.Hour)))
+	}
+}
+
+// CompassDirection converts a wind direction in degrees to a 16-point compass label
+func CompassDirection(degrees int) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+	degrees = ((degrees % 360) + 360) % 360
+	return points[(degrees*100+1125)/2250%16]
 }
 
-// FormatError formats an error message for stderr
+// UVIndexCategory returns the WHO exposure category for a UV index
+func UVIndexCategory(uv float64) string {
+	switch {
+	case uv < 3:
+		return "low"
+	case uv < 6:
+		return "moderate"
+	case uv < 8:
+		return "high"
+	case uv < 11:
+		return "very high"
+	default:
+		return "extreme"
+	}
+}
+
+// FormatError formats an error message for stderr, followed by a hint
+// for the errors sky knows how to recover from
 func FormatError(err error) string {
+	if hint := errorHint(err); hint != "" {
+		return fmt.Sprintf("Error: %s\nHint: %s\n", err.Error(), hint)
+	}
 	return fmt.Sprintf("Error: %s\n", err.Error())
 }
+
+// locationHeader returns the "Name, Country" line shown above weather data.
+// The country is omitted for locations without one, such as raw coordinates,
+// and reverse-geocoded coordinates read "near Name, Country (distance)".
+func locationHeader(location *api.Location) string {
+	header := location.Name
+	if location.Country != "" {
+		header += ", " + location.Country
+	}
+	if location.Distance > 0 {
+		header = fmt.Sprintf("near %s (%.1f km)", header, location.Distance)
+	}
+	return header
+}
+
+// formatTemperature formats a temperature with its unit symbol
+func formatTemperature(v float64, units api.Units) string {
+	return fmt.Sprintf("%.1f%s", v, units.Temperature.Symbol())
+}
+
+// styleTemperature colors formatted temperature text by the temperature v, given in units
+func styleTemperature(text string, v float64, units api.Units, style Style) string {
+	return style.Temperature(api.ConvertTemperature(v, units.Temperature, api.Celsius), text)
+}
+
+// formatWindSpeed formats a wind speed with its unit symbol
+func formatWindSpeed(v float64, units api.Units) string {
+	return fmt.Sprintf("%.1f %s", v, units.WindSpeed.Symbol())
+}
+
+// formatPrecipitation formats a precipitation amount with its unit symbol
+func formatPrecipitation(v float64, units api.Units) string {
+	if units.Precipitation == api.Inches {
+		return fmt.Sprintf("%.2f %s", v, units.Precipitation.Symbol())
+	}
+	return fmt.Sprintf("%.1f %s", v, units.Precipitation.Symbol())
+}
+
+// formatVisibility formats a visibility in meters as kilometers, or miles for imperial units
+func formatVisibility(meters float64, units api.Units) string {
+	if units.Precipitation == api.Inches {
+		return fmt.Sprintf("%.1f mi", meters/1609.344)
+	}
+	return fmt.Sprintf("%.1f km", meters/1000)
+}
+
+// padLeft right-aligns s in a column of width runes
+func padLeft(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
@@ -2,7 +2,9 @@ package ui
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kakkoiirus/sky-cli/internal/api"
 	"github.com/stretchr/testify/assert"
@@ -155,3 +157,192 @@ func TestFormatError_WrappedError(t *testing.T) {
 
 	assert.Equal(t, "Error: failed to fetch location: network unreachable\n", output)
 }
+
+func TestFormatWeather_Units(t *testing.T) {
+	location := &api.Location{Name: "Chicago", Country: "US"}
+
+	tests := []struct {
+		name     string
+		units    api.Units
+		expected string
+	}{
+		{"Metric by default", api.Units{}, "Temp: 71.2°C"},
+		{"Imperial", api.ImperialUnits, "Temp: 71.2°F"},
+		{"Scientific", api.ScientificUnits, "Temp: 71.2K"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weather := &api.Weather{Temperature: 71.2, WeatherCodeDesc: "Clear", Units: tt.units}
+
+			assert.Contains(t, FormatWeather(location, weather), tt.expected)
+		})
+	}
+}
+
+func TestPadLeft(t *testing.T) {
+	assert.Equal(t, "  1.0°C", padLeft("1.0°C", 7))
+	assert.Equal(t, "123.4°C", padLeft("123.4°C", 5))
+}
+
+func TestFormatWeatherDetail_Typical(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	weather := &api.Weather{
+		Temperature:     21.0,
+		ApparentTemp:    20.5,
+		WeatherCode:     1,
+		WeatherCodeDesc: "Mainly clear",
+		Humidity:        64,
+		DewPoint:        13.9,
+		WindSpeed:       14.2,
+		WindDirection:   225,
+		WindGusts:       31.7,
+		Pressure:        1008.4,
+		Precipitation:   0.2,
+		CloudCover:      35,
+		UVIndex:         5.35,
+		Visibility:      24140,
+		IsDay:           true,
+	}
+
+	output := FormatWeatherDetail(location, weather)
+
+	assert.True(t, strings.HasPrefix(output, FormatWeather(location, weather)))
+	assert.Contains(t, output, "Humidity: 64%\n")
+	assert.Contains(t, output, "Dew point: 13.9°C\n")
+	assert.Contains(t, output, "Wind: 14.2 km/h SW (gusts 31.7 km/h)\n")
+	assert.Contains(t, output, "Pressure: 1008.4 hPa\n")
+	assert.Contains(t, output, "Precipitation: 0.2 mm\n")
+	assert.Contains(t, output, "Cloud cover: 35%\n")
+	assert.Contains(t, output, "UV index: 5.3 (moderate)\n")
+	assert.Contains(t, output, "Visibility: 24.1 km\n")
+	assert.Contains(t, output, "Daylight: yes\n")
+}
+
+func TestFormatWeatherDetail_Imperial(t *testing.T) {
+	location := &api.Location{Name: "Chicago", Country: "US"}
+	weather := &api.Weather{
+		WeatherCodeDesc: "Clear",
+		WindSpeed:       8.1,
+		WindGusts:       15.0,
+		Precipitation:   0.04,
+		Visibility:      16093.44,
+		Units:           api.ImperialUnits,
+	}
+
+	output := FormatWeatherDetail(location, weather)
+
+	assert.Contains(t, output, "Wind: 8.1 mph N (gusts 15.0 mph)\n")
+	assert.Contains(t, output, "Precipitation: 0.04 in\n")
+	assert.Contains(t, output, "Visibility: 10.0 mi\n")
+	assert.Contains(t, output, "Daylight: no\n")
+
+	// Open-Meteo reports visibility in feet with imperial units
+	weather.Visibility, weather.VisibilityUnit = 52800, api.Feet
+	assert.Contains(t, FormatWeatherDetail(location, weather), "Visibility: 10.0 mi\n")
+}
+
+func TestCompassDirection(t *testing.T) {
+	tests := []struct {
+		degrees  int
+		expected string
+	}{
+		{0, "N"},
+		{11, "N"},
+		{12, "NNE"},
+		{90, "E"},
+		{200, "SSW"},
+		{225, "SW"},
+		{349, "N"},
+		{360, "N"},
+		{-90, "W"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, CompassDirection(tt.degrees), "degrees=%d", tt.degrees)
+	}
+}
+
+func TestUVIndexCategory(t *testing.T) {
+	assert.Equal(t, "low", UVIndexCategory(0))
+	assert.Equal(t, "moderate", UVIndexCategory(3))
+	assert.Equal(t, "high", UVIndexCategory(7.9))
+	assert.Equal(t, "very high", UVIndexCategory(10))
+	assert.Equal(t, "extreme", UVIndexCategory(11.2))
+}
+
+func TestFormatWeather_Coordinates(t *testing.T) {
+	location := &api.Location{Name: "35.6833°N 139.6833°E", Latitude: 35.6833, Longitude: 139.6833}
+	weather := &api.Weather{Temperature: 12.0, WeatherCodeDesc: "Clear"}
+
+	output := FormatWeather(location, weather)
+
+	assert.True(t, strings.HasPrefix(output, "35.6833°N 139.6833°E\nClear"))
+}
+
+func TestFormatWeather_ReverseGeocodedCoordinates(t *testing.T) {
+	location := &api.Location{Name: "Hakone", Country: "JP", Latitude: 35.2412, Longitude: 139.0786, Distance: 2.74}
+	weather := &api.Weather{Temperature: 9.5, WeatherCodeDesc: "Foggy"}
+
+	output := FormatWeather(location, weather)
+
+	assert.True(t, strings.HasPrefix(output, "near Hakone, JP (2.7 km)\nFoggy"))
+}
+
+func TestFormatWeather_Stale(t *testing.T) {
+	location := &api.Location{Name: "Berlin", Country: "DE"}
+	weather := &api.Weather{
+		Temperature:     18.5,
+		WeatherCodeDesc: "Clear",
+		FetchedAt:       time.Now().Add(-12*time.Minute - 30*time.Second),
+		Stale:           true,
+	}
+
+	output := FormatWeather(location, weather)
+	assert.True(t, strings.HasSuffix(output, "Feels like: 0.0°C\n(cached, updated 12 min ago)\n"), output)
+
+	// The marker closes the detailed view too
+	detail := FormatWeatherDetail(location, weather)
+	assert.True(t, strings.HasSuffix(detail, "Daylight: no\n(cached, updated 12 min ago)\n"), detail)
+
+	weather.Stale = false
+	assert.NotContains(t, FormatWeather(location, weather), "cached")
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5 min ago"},
+		{90 * time.Minute, "1 h ago"},
+		{47 * time.Hour, "47 h ago"},
+		{72 * time.Hour, "3 days ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatAge(tt.age))
+		})
+	}
+}
+
+func TestFormatWeatherStyled_Colors(t *testing.T) {
+	location := &api.Location{Name: "Chicago", Country: "US"}
+	weather := &api.Weather{
+		Temperature:     -40,
+		ApparentTemp:    104,
+		WeatherCode:     63,
+		WeatherCodeDesc: "Moderate rain",
+		Units:           api.ImperialUnits,
+	}
+
+	output := FormatWeatherStyled(location, weather, Style{Mode: TrueColor})
+
+	// -40°F is the coldest end of the gradient, 104°F the hottest
+	assert.Contains(t, output, "\x1b[38;2;58;142;240mModerate rain\x1b[0m 🌧️\n")
+	assert.Contains(t, output, "Temp: \x1b[38;2;142;107;216m-40.0°F\x1b[0m\n")
+	assert.Contains(t, output, "Feels like: \x1b[38;2;232;48;48m104.0°F\x1b[0m\n")
+	assert.Equal(t, FormatWeather(location, weather), FormatWeatherStyled(location, weather, Style{}))
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// FormatHourlyForecast formats an hour-by-hour forecast for display
+func FormatHourlyForecast(location *api.Location, forecast *api.HourlyForecast) string {
+	return FormatHourlyForecastStyled(location, forecast, Style{})
+}
+
+// FormatHourlyForecastStyled formats an hour-by-hour forecast in the colors of style
+func FormatHourlyForecastStyled(location *api.Location, forecast *api.HourlyForecast, style Style) string {
+	var b strings.Builder
+	units := forecast.Units
+
+	fmt.Fprintf(&b, "%s\n", locationHeader(location))
+	fmt.Fprintf(&b, "Next %d hours:\n", len(forecast.Hours))
+
+	for _, hour := range forecast.Hours {
+		fmt.Fprintf(&b, "%s  %s  feels %s  rain %3d%%  wind %s  %s %s\n",
+			hour.Time.Format("Mon 15:04"),
+			styleTemperature(padLeft(formatTemperature(hour.Temperature, units), 7), hour.Temperature, units, style),
+			styleTemperature(padLeft(formatTemperature(hour.ApparentTemp, units), 7), hour.ApparentTemp, units, style),
+			hour.PrecipitationProbability,
+			padLeft(formatWindSpeed(hour.WindSpeed, units), 9),
+			style.Condition(hour.WeatherCode, hour.WeatherCodeDesc),
+			api.WeatherCodeEmoji(hour.WeatherCode),
+		)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatHourlyForecast_Typical(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	zone := time.FixedZone("JST", 9*60*60)
+
+	forecast := &api.HourlyForecast{Hours: []api.HourlyWeather{
+		{
+			Time:                     time.Date(2025, 3, 1, 21, 0, 0, 0, zone),
+			Temperature:              8.4,
+			ApparentTemp:             5.1,
+			PrecipitationProbability: 15,
+			WeatherCode:              3,
+			WeatherCodeDesc:          "Overcast",
+			WindSpeed:                11.2,
+		},
+		{
+			Time:                     time.Date(2025, 3, 1, 22, 0, 0, 0, zone),
+			Temperature:              -7.9,
+			ApparentTemp:             -12.6,
+			PrecipitationProbability: 100,
+			WeatherCode:              61,
+			WeatherCodeDesc:          "Slight rain",
+			WindSpeed:                4.8,
+		},
+	}}
+
+	output := FormatHourlyForecast(location, forecast)
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "Tokyo, JP", lines[0])
+	assert.Equal(t, "Next 2 hours:", lines[1])
+	assert.Equal(t, "Sat 21:00    8.4°C  feels   5.1°C  rain  15%  wind 11.2 km/h  Overcast ☁️", lines[2])
+	assert.Equal(t, "Sat 22:00   -7.9°C  feels -12.6°C  rain 100%  wind  4.8 km/h  Slight rain 🌧️", lines[3])
+}
+
+func TestFormatHourlyForecast_Empty(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+
+	output := FormatHourlyForecast(location, &api.HourlyForecast{})
+
+	assert.Equal(t, "Tokyo, JP\nNext 0 hours:\n", output)
+}
+
+func TestFormatHourlyForecast_Imperial(t *testing.T) {
+	location := &api.Location{Name: "Chicago", Country: "US"}
+	forecast := &api.HourlyForecast{
+		Units: api.ImperialUnits,
+		Hours: []api.HourlyWeather{{
+			Time:            time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			Temperature:     41.5,
+			ApparentTemp:    35.2,
+			WindSpeed:       12.4,
+			WeatherCodeDesc: "Clear",
+		}},
+	}
+
+	output := FormatHourlyForecast(location, forecast)
+
+	assert.Contains(t, output, "Sat 09:00   41.5°F  feels  35.2°F  rain   0%  wind  12.4 mph  Clear")
+}
+
+func TestFormatHourlyForecastStyled_KeepsAlignment(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	forecast := &api.HourlyForecast{Hours: []api.HourlyWeather{{
+		Time:            time.Date(2025, 3, 1, 21, 0, 0, 0, time.UTC),
+		Temperature:     8.4,
+		ApparentTemp:    5.1,
+		WeatherCode:     3,
+		WeatherCodeDesc: "Overcast",
+	}}}
+
+	output := FormatHourlyForecastStyled(location, forecast, Style{Mode: Color16})
+	plain := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(output, "")
+
+	assert.Contains(t, output, "\x1b[")
+	assert.Equal(t, FormatHourlyForecast(location, forecast), plain)
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// SchemaVersion is the version of the JSON output schema documented in
+// docs/json-schema.md. It is bumped when a field is removed or changes
+// meaning; fields may be added without a new version.
+const SchemaVersion = 1
+
+// jsonHeader starts every JSON object sky prints
+type jsonHeader struct {
+	SchemaVersion int    `json:"schema_version"`
+	Type          string `json:"type"`
+}
+
+// jsonValue is a measurement with its unit
+type jsonValue struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+type jsonLocation struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Country     string  `json:"country_code,omitempty"`
+	Admin1      string  `json:"admin1,omitempty"`
+	Admin2      string  `json:"admin2,omitempty"`
+	Population  int     `json:"population,omitempty"`
+	Timezone    string  `json:"timezone,omitempty"`
+	FeatureCode string  `json:"feature_code,omitempty"`
+	DistanceKm  float64 `json:"distance_km,omitempty"`
+}
+
+type jsonCurrent struct {
+	jsonHeader
+	Location  jsonLocation `json:"location"`
+	FetchedAt *time.Time   `json:"fetched_at,omitempty"`
+	Stale     bool         `json:"stale"`
+	Current   struct {
+		Temperature         jsonValue `json:"temperature"`
+		ApparentTemperature jsonValue `json:"apparent_temperature"`
+		WeatherCode         int       `json:"weather_code"`
+		Description         string    `json:"description"`
+		Humidity            jsonValue `json:"humidity"`
+		DewPoint            jsonValue `json:"dew_point"`
+		WindSpeed           jsonValue `json:"wind_speed"`
+		WindDirection       jsonValue `json:"wind_direction"`
+		WindGusts           jsonValue `json:"wind_gusts"`
+		Pressure            jsonValue `json:"pressure"`
+		Precipitation       jsonValue `json:"precipitation"`
+		CloudCover          jsonValue `json:"cloud_cover"`
+		UVIndex             float64   `json:"uv_index"`
+		Visibility          jsonValue `json:"visibility"`
+		IsDay               bool      `json:"is_day"`
+	} `json:"current"`
+}
+
+type jsonHour struct {
+	jsonHeader
+	Location                 jsonLocation `json:"location"`
+	FetchedAt                *time.Time   `json:"fetched_at,omitempty"`
+	Time                     time.Time    `json:"time"`
+	Temperature              jsonValue    `json:"temperature"`
+	ApparentTemperature      jsonValue    `json:"apparent_temperature"`
+	PrecipitationProbability jsonValue    `json:"precipitation_probability"`
+	WeatherCode              int          `json:"weather_code"`
+	Description              string       `json:"description"`
+	WindSpeed                jsonValue    `json:"wind_speed"`
+	WindDirection            jsonValue    `json:"wind_direction"`
+}
+
+type jsonDay struct {
+	jsonHeader
+	Location                 jsonLocation `json:"location"`
+	FetchedAt                *time.Time   `json:"fetched_at,omitempty"`
+	Date                     string       `json:"date"`
+	TemperatureMax           jsonValue    `json:"temperature_max"`
+	TemperatureMin           jsonValue    `json:"temperature_min"`
+	PrecipitationSum         jsonValue    `json:"precipitation_sum"`
+	PrecipitationProbability jsonValue    `json:"precipitation_probability"`
+	WeatherCode              int          `json:"weather_code"`
+	Description              string       `json:"description"`
+	Sunrise                  *time.Time   `json:"sunrise"`
+	Sunset                   *time.Time   `json:"sunset"`
+	WindSpeedMax             jsonValue    `json:"wind_speed_max"`
+}
+
+type jsonSearchResult struct {
+	jsonHeader
+	Rank     int          `json:"rank"`
+	Location jsonLocation `json:"location"`
+}
+
+type jsonAirQuality struct {
+	jsonHeader
+	Location            jsonLocation `json:"location"`
+	FetchedAt           *time.Time   `json:"fetched_at,omitempty"`
+	USAQI               int          `json:"us_aqi"`
+	USAQICategory       string       `json:"us_aqi_category"`
+	EuropeanAQI         int          `json:"european_aqi"`
+	EuropeanAQICategory string       `json:"european_aqi_category"`
+	PM25                jsonValue    `json:"pm2_5"`
+	PM10                jsonValue    `json:"pm10"`
+	Ozone               jsonValue    `json:"ozone"`
+	NitrogenDioxide     jsonValue    `json:"nitrogen_dioxide"`
+	SulphurDioxide      jsonValue    `json:"sulphur_dioxide"`
+	CarbonMonoxide      jsonValue    `json:"carbon_monoxide"`
+	Pollen              []jsonPollen `json:"pollen"`
+}
+
+type jsonPollen struct {
+	Type string `json:"type"`
+	jsonValue
+}
+
+type jsonError struct {
+	jsonHeader
+	Error struct {
+		Message  string `json:"message"`
+		Class    string `json:"class"`
+		ExitCode int    `json:"exit_code"`
+		Hint     string `json:"hint,omitempty"`
+	} `json:"error"`
+}
+
+// FormatWeatherJSON formats current conditions as a single-line JSON object
+func FormatWeatherJSON(location *api.Location, weather *api.Weather) (string, error) {
+	units := weather.Units
+	temp := units.Temperature.Symbol()
+	wind := units.WindSpeed.Symbol()
+	precip := units.Precipitation.Symbol()
+
+	doc := jsonCurrent{
+		jsonHeader: jsonHeader{SchemaVersion, "current"},
+		Location:   toJSONLocation(location),
+		FetchedAt:  fetchedAt(weather.FetchedAt),
+		Stale:      weather.Stale,
+	}
+	cur := &doc.Current
+	cur.Temperature = jsonValue{weather.Temperature, temp}
+	cur.ApparentTemperature = jsonValue{weather.ApparentTemp, temp}
+	cur.WeatherCode = weather.WeatherCode
+	cur.Description = weather.WeatherCodeDesc
+	cur.Humidity = jsonValue{float64(weather.Humidity), "%"}
+	cur.DewPoint = jsonValue{weather.DewPoint, temp}
+	cur.WindSpeed = jsonValue{weather.WindSpeed, wind}
+	cur.WindDirection = jsonValue{float64(weather.WindDirection), "°"}
+	cur.WindGusts = jsonValue{weather.WindGusts, wind}
+	cur.Pressure = jsonValue{weather.Pressure, "hPa"}
+	cur.Precipitation = jsonValue{weather.Precipitation, precip}
+	cur.CloudCover = jsonValue{float64(weather.CloudCover), "%"}
+	cur.UVIndex = weather.UVIndex
+	cur.Visibility = jsonValue{weather.Visibility, weather.VisibilityUnit.Symbol()}
+	cur.IsDay = weather.IsDay
+
+	return jsonLine(doc)
+}
+
+// FormatHourlyJSON formats an hourly forecast as newline-delimited JSON, one object per hour
+func FormatHourlyJSON(location *api.Location, forecast *api.HourlyForecast) (string, error) {
+	var b strings.Builder
+	temp := forecast.Units.Temperature.Symbol()
+	wind := forecast.Units.WindSpeed.Symbol()
+	loc := toJSONLocation(location)
+
+	for _, h := range forecast.Hours {
+		line, err := jsonLine(jsonHour{
+			jsonHeader:               jsonHeader{SchemaVersion, "hourly"},
+			Location:                 loc,
+			FetchedAt:                fetchedAt(forecast.FetchedAt),
+			Time:                     h.Time,
+			Temperature:              jsonValue{h.Temperature, temp},
+			ApparentTemperature:      jsonValue{h.ApparentTemp, temp},
+			PrecipitationProbability: jsonValue{float64(h.PrecipitationProbability), "%"},
+			WeatherCode:              h.WeatherCode,
+			Description:              h.WeatherCodeDesc,
+			WindSpeed:                jsonValue{h.WindSpeed, wind},
+			WindDirection:            jsonValue{float64(h.WindDirection), "°"},
+		})
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(line)
+	}
+	return b.String(), nil
+}
+
+// FormatDailyJSON formats a daily forecast as newline-delimited JSON, one object per day
+func FormatDailyJSON(location *api.Location, forecast *api.DailyForecast) (string, error) {
+	var b strings.Builder
+	temp := forecast.Units.Temperature.Symbol()
+	wind := forecast.Units.WindSpeed.Symbol()
+	precip := forecast.Units.Precipitation.Symbol()
+	loc := toJSONLocation(location)
+
+	for _, d := range forecast.Days {
+		line, err := jsonLine(jsonDay{
+			jsonHeader:               jsonHeader{SchemaVersion, "daily"},
+			Location:                 loc,
+			FetchedAt:                fetchedAt(forecast.FetchedAt),
+			Date:                     d.Date.Format(time.DateOnly),
+			TemperatureMax:           jsonValue{d.TemperatureMax, temp},
+			TemperatureMin:           jsonValue{d.TemperatureMin, temp},
+			PrecipitationSum:         jsonValue{d.PrecipitationSum, precip},
+			PrecipitationProbability: jsonValue{float64(d.PrecipitationProbability), "%"},
+			WeatherCode:              d.WeatherCode,
+			Description:              d.WeatherCodeDesc,
+			Sunrise:                  jsonTime(d.Sunrise),
+			Sunset:                   jsonTime(d.Sunset),
+			WindSpeedMax:             jsonValue{d.WindSpeedMax, wind},
+		})
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(line)
+	}
+	return b.String(), nil
+}
+
+// FormatLocationsJSON formats search results as newline-delimited JSON, best match first
+func FormatLocationsJSON(locations []api.Location) (string, error) {
+	var b strings.Builder
+	for i := range locations {
+		line, err := jsonLine(jsonSearchResult{
+			jsonHeader: jsonHeader{SchemaVersion, "location"},
+			Rank:       i + 1,
+			Location:   toJSONLocation(&locations[i]),
+		})
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(line)
+	}
+	return b.String(), nil
+}
+
+// FormatAirQualityJSON formats air quality data as a single-line JSON object
+func FormatAirQualityJSON(location *api.Location, aq *api.AirQuality) (string, error) {
+	const concentration = "µg/m³"
+
+	doc := jsonAirQuality{
+		jsonHeader:          jsonHeader{SchemaVersion, "air_quality"},
+		Location:            toJSONLocation(location),
+		FetchedAt:           fetchedAt(aq.FetchedAt),
+		USAQI:               aq.USAQI,
+		USAQICategory:       api.USAQICategory(aq.USAQI).Label,
+		EuropeanAQI:         aq.EuropeanAQI,
+		EuropeanAQICategory: api.EuropeanAQICategory(aq.EuropeanAQI).Label,
+		PM25:                jsonValue{aq.PM25, concentration},
+		PM10:                jsonValue{aq.PM10, concentration},
+		Ozone:               jsonValue{aq.Ozone, concentration},
+		NitrogenDioxide:     jsonValue{aq.NitrogenDioxide, concentration},
+		SulphurDioxide:      jsonValue{aq.SulphurDioxide, concentration},
+		CarbonMonoxide:      jsonValue{aq.CarbonMonoxide, concentration},
+		Pollen:              []jsonPollen{},
+	}
+	for _, p := range aq.Pollen {
+		doc.Pollen = append(doc.Pollen, jsonPollen{Type: p.Type, jsonValue: jsonValue{p.Count, "grains/m³"}})
+	}
+	return jsonLine(doc)
+}
+
+// FormatErrorJSON formats an error as a single-line JSON object for stderr.
+// class names the error class and code is the exit code sky returns for it.
+func FormatErrorJSON(err error, class string, code int) string {
+	doc := jsonError{jsonHeader: jsonHeader{SchemaVersion, "error"}}
+	doc.Error.Message = err.Error()
+	doc.Error.Class = class
+	doc.Error.ExitCode = code
+	doc.Error.Hint = errorHint(err)
+	// Strings and integers always encode
+	line, _ := jsonLine(doc)
+	return line
+}
+
+func toJSONLocation(location *api.Location) jsonLocation {
+	return jsonLocation{
+		Name:        location.Name,
+		Latitude:    location.Latitude,
+		Longitude:   location.Longitude,
+		Country:     location.Country,
+		Admin1:      location.Admin1,
+		Admin2:      location.Admin2,
+		Population:  location.Population,
+		Timezone:    location.Timezone,
+		FeatureCode: location.FeatureCode,
+		DistanceKm:  location.Distance,
+	}
+}
+
+// jsonTime returns t for encoding, or nil when t is unknown
+func jsonTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// fetchedAt returns a fetch time in UTC to the second, or nil when it is unknown
+func fetchedAt(t time.Time) *time.Time {
+	return jsonTime(t.UTC().Truncate(time.Second))
+}
+
+// jsonLine encodes v as one line of JSON.
+// It fails for numbers JSON cannot represent, such as NaN.
+func jsonLine(v any) (string, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return b.String(), nil
+}
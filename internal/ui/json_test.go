@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// decodeLines decodes each line of newline-delimited JSON
+func decodeLines(t *testing.T, output string) []map[string]any {
+	t.Helper()
+	require.True(t, strings.HasSuffix(output, "\n"))
+
+	var docs []map[string]any
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &doc), line)
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func TestFormatWeatherJSON(t *testing.T) {
+	location := &api.Location{Name: "Berlin", Country: "DE", Admin1: "Land Berlin", Latitude: 52.52437, Longitude: 13.41053, Timezone: "Europe/Berlin"}
+	weather := &api.Weather{
+		Temperature:     18.5,
+		ApparentTemp:    17.2,
+		WeatherCode:     2,
+		WeatherCodeDesc: "Partly cloudy",
+		Humidity:        64,
+		WindSpeed:       8.1,
+		WindDirection:   225,
+		Pressure:        1008.4,
+		Visibility:      79200,
+		VisibilityUnit:  api.Feet,
+		IsDay:           true,
+		Units:           api.ImperialUnits,
+		FetchedAt:       time.Date(2025, 3, 1, 13, 0, 5, 500, time.FixedZone("", 3600)),
+	}
+
+	output, err := FormatWeatherJSON(location, weather)
+	require.NoError(t, err)
+	docs := decodeLines(t, output)
+	require.Len(t, docs, 1)
+	doc := docs[0]
+
+	assert.Equal(t, float64(SchemaVersion), doc["schema_version"])
+	assert.Equal(t, "current", doc["type"])
+	assert.Equal(t, "2025-03-01T12:00:05Z", doc["fetched_at"])
+	assert.Equal(t, false, doc["stale"])
+	assert.Equal(t, map[string]any{
+		"name":         "Berlin",
+		"latitude":     52.52437,
+		"longitude":    13.41053,
+		"country_code": "DE",
+		"admin1":       "Land Berlin",
+		"timezone":     "Europe/Berlin",
+	}, doc["location"])
+
+	current := doc["current"].(map[string]any)
+	assert.Equal(t, map[string]any{"value": 18.5, "unit": "°F"}, current["temperature"])
+	assert.Equal(t, map[string]any{"value": 8.1, "unit": "mph"}, current["wind_speed"])
+	assert.Equal(t, map[string]any{"value": 64.0, "unit": "%"}, current["humidity"])
+	assert.Equal(t, map[string]any{"value": 225.0, "unit": "°"}, current["wind_direction"])
+	assert.Equal(t, map[string]any{"value": 1008.4, "unit": "hPa"}, current["pressure"])
+	assert.Equal(t, map[string]any{"value": 0.0, "unit": "in"}, current["precipitation"])
+	assert.Equal(t, map[string]any{"value": 79200.0, "unit": "ft"}, current["visibility"])
+	assert.Equal(t, "Partly cloudy", current["description"])
+	assert.Equal(t, 2.0, current["weather_code"])
+	assert.Equal(t, true, current["is_day"])
+	assert.Len(t, current, 15)
+}
+
+func TestFormatWeatherJSON_UnknownFetchTime(t *testing.T) {
+	output, err := FormatWeatherJSON(&api.Location{Name: "Berlin"}, &api.Weather{Stale: true})
+	require.NoError(t, err)
+
+	doc := decodeLines(t, output)[0]
+	assert.NotContains(t, doc, "fetched_at")
+	assert.Equal(t, true, doc["stale"])
+}
+
+func TestFormatJSON_NonFiniteNumbers(t *testing.T) {
+	// A value JSON cannot represent is an error, not a crash
+	_, err := FormatWeatherJSON(&api.Location{Name: "Berlin"}, &api.Weather{Temperature: math.NaN()})
+	assert.ErrorContains(t, err, "failed to encode JSON")
+
+	_, err = FormatDailyJSON(&api.Location{Name: "Berlin"}, &api.DailyForecast{Days: []api.DailyWeather{{TemperatureMax: math.Inf(1)}}})
+	assert.ErrorContains(t, err, "failed to encode JSON")
+}
+
+func TestFormatHourlyJSON(t *testing.T) {
+	zone := time.FixedZone("", 9*60*60)
+	forecast := &api.HourlyForecast{
+		Hours: []api.HourlyWeather{
+			{Time: time.Date(2025, 3, 1, 21, 0, 0, 0, zone), Temperature: 8.4, PrecipitationProbability: 15, WeatherCodeDesc: "Overcast"},
+			{Time: time.Date(2025, 3, 1, 22, 0, 0, 0, zone), Temperature: 7.9, PrecipitationProbability: 70, WeatherCodeDesc: "Slight rain"},
+		},
+		Units: api.MetricUnits,
+	}
+
+	output, err := FormatHourlyJSON(&api.Location{Name: "Tokyo"}, forecast)
+	require.NoError(t, err)
+	docs := decodeLines(t, output)
+
+	require.Len(t, docs, 2)
+	assert.Equal(t, "hourly", docs[1]["type"])
+	assert.Equal(t, "2025-03-01T22:00:00+09:00", docs[1]["time"])
+	assert.Equal(t, map[string]any{"value": 7.9, "unit": "°C"}, docs[1]["temperature"])
+	assert.Equal(t, map[string]any{"value": 70.0, "unit": "%"}, docs[1]["precipitation_probability"])
+	assert.Equal(t, "Tokyo", docs[1]["location"].(map[string]any)["name"])
+}
+
+func TestFormatDailyJSON(t *testing.T) {
+	forecast := &api.DailyForecast{
+		Days: []api.DailyWeather{
+			{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), TemperatureMax: 12.3, Sunrise: time.Date(2025, 3, 1, 6, 58, 0, 0, time.UTC)},
+			{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), TemperatureMax: 9.8},
+		},
+		Units: api.MetricUnits,
+	}
+
+	output, err := FormatDailyJSON(&api.Location{Name: "Berlin"}, forecast)
+	require.NoError(t, err)
+	docs := decodeLines(t, output)
+
+	require.Len(t, docs, 2)
+	assert.Equal(t, "daily", docs[0]["type"])
+	assert.Equal(t, "2025-03-01", docs[0]["date"])
+	assert.Equal(t, "2025-03-01T06:58:00Z", docs[0]["sunrise"])
+	assert.Equal(t, map[string]any{"value": 12.3, "unit": "°C"}, docs[0]["temperature_max"])
+	// Polar days have no sunrise
+	assert.Nil(t, docs[1]["sunrise"])
+	assert.Contains(t, docs[1], "sunrise")
+}
+
+func TestFormatLocationsJSON(t *testing.T) {
+	locations := []api.Location{
+		{Name: "Paris", Country: "FR", Population: 2138551},
+		{Name: "Paris", Country: "US", Admin1: "Texas"},
+	}
+
+	output, err := FormatLocationsJSON(locations)
+	require.NoError(t, err)
+	docs := decodeLines(t, output)
+
+	require.Len(t, docs, 2)
+	assert.Equal(t, "location", docs[1]["type"])
+	assert.Equal(t, 2.0, docs[1]["rank"])
+	assert.Equal(t, "Texas", docs[1]["location"].(map[string]any)["admin1"])
+	assert.Equal(t, 2138551.0, docs[0]["location"].(map[string]any)["population"])
+}
+
+func TestFormatAirQualityJSON(t *testing.T) {
+	aq := &api.AirQuality{USAQI: 42, EuropeanAQI: 25, PM25: 8.1, Pollen: []api.PollenCount{{Type: "birch", Count: 12}}}
+
+	output, err := FormatAirQualityJSON(&api.Location{Name: "Berlin"}, aq)
+	require.NoError(t, err)
+	doc := decodeLines(t, output)[0]
+
+	assert.Equal(t, "air_quality", doc["type"])
+	assert.Equal(t, "Good", doc["us_aqi_category"])
+	assert.Equal(t, "Fair", doc["european_aqi_category"])
+	assert.Equal(t, map[string]any{"value": 8.1, "unit": "µg/m³"}, doc["pm2_5"])
+	assert.Equal(t, []any{map[string]any{"type": "birch", "value": 12.0, "unit": "grains/m³"}}, doc["pollen"])
+}
+
+func TestFormatErrorJSON(t *testing.T) {
+	doc := decodeLines(t, FormatErrorJSON(api.ErrLocationNotFound, "not_found", 3))[0]
+
+	assert.Equal(t, "error", doc["type"])
+	errObj := doc["error"].(map[string]any)
+	assert.Equal(t, "location not found", errObj["message"])
+	assert.Equal(t, "not_found", errObj["class"])
+	assert.Equal(t, 3.0, errObj["exit_code"])
+	assert.Contains(t, errObj["hint"], "sky search")
+
+	doc = decodeLines(t, FormatErrorJSON(errors.New("boom"), "failure", 1))[0]
+	assert.NotContains(t, doc["error"], "hint")
+}
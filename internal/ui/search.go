@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// FormatLocations formats geocoding candidates as a numbered list
+func FormatLocations(locations []api.Location) string {
+	var b strings.Builder
+
+	width := len(strconv.Itoa(len(locations)))
+	for i, location := range locations {
+		fmt.Fprintf(&b, "%*d. %s\n", width, i+1, LocationLabel(&location))
+
+		details := []string{fmt.Sprintf("%.4f, %.4f", location.Latitude, location.Longitude)}
+		if location.Timezone != "" {
+			details = append(details, location.Timezone)
+		}
+		if location.Population > 0 {
+			details = append(details, "pop. "+formatThousands(location.Population))
+		}
+		if location.FeatureCode != "" {
+			details = append(details, location.FeatureCode)
+		}
+		fmt.Fprintf(&b, "%*s  %s\n", width, "", strings.Join(details, " · "))
+	}
+
+	return b.String()
+}
+
+// LocationLabel returns the name of a location with its administrative areas and country,
+// skipping areas that repeat the name
+func LocationLabel(location *api.Location) string {
+	parts := []string{location.Name}
+	for _, area := range []string{location.Admin2, location.Admin1} {
+		if area != "" && area != parts[len(parts)-1] {
+			parts = append(parts, area)
+		}
+	}
+	if location.Country != "" {
+		parts = append(parts, location.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatThousands formats n with comma thousands separators
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	s := strconv.Itoa(n)
+
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatLocations_Typical(t *testing.T) {
+	locations := []api.Location{
+		{
+			Name:        "Paris",
+			Latitude:    48.85341,
+			Longitude:   2.3488,
+			Country:     "FR",
+			Admin1:      "Île-de-France",
+			Admin2:      "Paris",
+			Population:  2138551,
+			Timezone:    "Europe/Paris",
+			FeatureCode: "PPLC",
+		},
+		{
+			Name:      "Paris",
+			Latitude:  33.66094,
+			Longitude: -95.55551,
+			Country:   "US",
+			Admin1:    "Texas",
+			Admin2:    "Lamar",
+		},
+	}
+
+	output := FormatLocations(locations)
+
+	assert.Equal(t, "1. Paris, Île-de-France, FR\n"+
+		"   48.8534, 2.3488 · Europe/Paris · pop. 2,138,551 · PPLC\n"+
+		"2. Paris, Lamar, Texas, US\n"+
+		"   33.6609, -95.5555\n", output)
+}
+
+func TestFormatLocations_AlignsTwoDigitNumbers(t *testing.T) {
+	locations := make([]api.Location, 10)
+	for i := range locations {
+		locations[i] = api.Location{Name: "Springfield", Country: "US"}
+	}
+
+	output := FormatLocations(locations)
+
+	assert.Contains(t, output, " 1. Springfield, US\n")
+	assert.Contains(t, output, "10. Springfield, US\n")
+}
+
+func TestLocationLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		location api.Location
+		expected string
+	}{
+		{"Name only", api.Location{Name: "Atlantis"}, "Atlantis"},
+		{"Name and country", api.Location{Name: "Tokyo", Country: "JP"}, "Tokyo, JP"},
+		{"Admin areas", api.Location{Name: "Springfield", Admin2: "Sangamon", Admin1: "Illinois", Country: "US"}, "Springfield, Sangamon, Illinois, US"},
+		{"Repeated admin area", api.Location{Name: "Berlin", Admin1: "Berlin", Admin2: "Berlin", Country: "DE"}, "Berlin, DE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LocationLabel(&tt.location))
+		})
+	}
+}
+
+func TestFormatThousands(t *testing.T) {
+	assert.Equal(t, "0", formatThousands(0))
+	assert.Equal(t, "999", formatThousands(999))
+	assert.Equal(t, "1,000", formatThousands(1000))
+	assert.Equal(t, "37,400,068", formatThousands(37400068))
+	assert.Equal(t, "-12,345", formatThousands(-12345))
+}
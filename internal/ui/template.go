@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+// TemplateData is the data model of user templates, documented in docs/templates.md
+type TemplateData struct {
+	Location *api.Location
+	Weather  *api.Weather
+
+	// Units holds the symbols of the units of Weather
+	Units TemplateUnits
+
+	// Header is the location line of the text output, e.g. "Tokyo, JP"
+	Header string
+
+	// Emoji illustrates the weather code
+	Emoji string
+
+	// Compass is the 16-point direction the wind blows from, e.g. "SW"
+	Compass string
+
+	// UVCategory is the WHO exposure category of the UV index
+	UVCategory string
+
+	// Age tells how long ago the conditions were fetched, e.g. "5 min ago"
+	Age string
+}
+
+// TemplateUnits are unit symbols such as "°C", "km/h" and "mm"
+type TemplateUnits struct {
+	Temp   string
+	Wind   string
+	Precip string
+}
+
+// TemperatureIn returns the temperature converted to unit, e.g. "fahrenheit" or "F"
+func (d TemplateData) TemperatureIn(unit string) (float64, error) {
+	to, err := api.ParseTemperatureUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	return api.ConvertTemperature(d.Weather.Temperature, d.Weather.Units.Temperature, to), nil
+}
+
+// FeelsLikeIn returns the apparent temperature converted to unit
+func (d TemplateData) FeelsLikeIn(unit string) (float64, error) {
+	to, err := api.ParseTemperatureUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	return api.ConvertTemperature(d.Weather.ApparentTemp, d.Weather.Units.Temperature, to), nil
+}
+
+// WindSpeedIn returns the wind speed converted to unit, e.g. "mph" or "kn"
+func (d TemplateData) WindSpeedIn(unit string) (float64, error) {
+	to, err := api.ParseWindSpeedUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	return api.ConvertWindSpeed(d.Weather.WindSpeed, d.Weather.Units.WindSpeed, to), nil
+}
+
+// PrecipitationIn returns the precipitation converted to unit, "mm" or "inch"
+func (d TemplateData) PrecipitationIn(unit string) (float64, error) {
+	to, err := api.ParsePrecipitationUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	return api.ConvertPrecipitation(d.Weather.Precipitation, d.Weather.Units.Precipitation, to), nil
+}
+
+// Template renders current conditions with a user-defined Go template
+type Template struct {
+	tmpl *template.Template
+}
+
+// templateColors are the ANSI codes of the names accepted by the color helper
+var templateColors = map[string]string{
+	"bold":    "1",
+	"dim":     "2",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"gray":    "90",
+}
+
+// ParseTemplate parses a template for TemplateData.
+// Without a color mode in style, the color helper returns its text unchanged.
+func ParseTemplate(text string, style Style) (*Template, error) {
+	funcs := template.FuncMap{
+		"round": func(v float64) int {
+			return int(math.Round(v))
+		},
+		"fixed": func(digits int, v float64) string {
+			return fmt.Sprintf("%.*f", digits, v)
+		},
+		"pad": func(width int, v any) string {
+			return padLeft(fmt.Sprint(v), width)
+		},
+		"padRight": func(width int, v any) string {
+			s := fmt.Sprint(v)
+			return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"color": func(name string, v any) (string, error) {
+			code, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color %q", name)
+			}
+			if style.Mode == NoColor {
+				return fmt.Sprint(v), nil
+			}
+			return "\033[" + code + "m" + fmt.Sprint(v) + "\033[0m", nil
+		},
+		"compass": CompassDirection,
+	}
+
+	tmpl, err := template.New("sky").Option("missingkey=error").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute renders the template for the conditions at location.
+// A newline is added when the output does not end with one.
+func (t *Template) Execute(location *api.Location, weather *api.Weather) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, NewTemplateData(location, weather)); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	out := b.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// NewTemplateData builds the template data model for the conditions at location
+func NewTemplateData(location *api.Location, weather *api.Weather) TemplateData {
+	data := TemplateData{
+		Location: location,
+		Weather:  weather,
+		Units: TemplateUnits{
+			Temp:   weather.Units.Temperature.Symbol(),
+			Wind:   weather.Units.WindSpeed.Symbol(),
+			Precip: weather.Units.Precipitation.Symbol(),
+		},
+		Header:     locationHeader(location),
+		Emoji:      api.WeatherCodeEmoji(weather.WeatherCode),
+		Compass:    CompassDirection(weather.WindDirection),
+		UVCategory: UVIndexCategory(weather.UVIndex),
+	}
+	if !weather.FetchedAt.IsZero() {
+		data.Age = FormatAge(time.Since(weather.FetchedAt))
+	}
+	return data
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kakkoiirus/sky-cli/internal/api"
+)
+
+func TestTemplate_Execute(t *testing.T) {
+	location := &api.Location{Name: "Tokyo", Country: "JP"}
+	weather := &api.Weather{
+		Temperature:     18.46,
+		ApparentTemp:    17.2,
+		WeatherCode:     0,
+		WeatherCodeDesc: "Clear",
+		WindSpeed:       36,
+		WindDirection:   225,
+		Precipitation:   12.7,
+		UVIndex:         6.2,
+		Units:           api.MetricUnits,
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Request example", "{{.Location.Name}}: {{.Weather.Temperature | round}}{{.Units.Temp}} {{.Emoji}}", "Tokyo: 18°C ☀️\n"},
+		{"Fixed digits", "{{fixed 1 .Weather.Temperature}}", "18.5\n"},
+		{"Padding", "[{{pad 6 .Location.Name}}][{{padRight 6 .Location.Country}}]", "[ Tokyo][JP    ]\n"},
+		{"Temperature conversion", "{{.TemperatureIn \"F\" | round}}°F", "65°F\n"},
+		{"Feels like conversion", "{{.FeelsLikeIn \"kelvin\" | fixed 2}}", "290.35\n"},
+		{"Wind conversion", "{{.WindSpeedIn \"m/s\" | round}} m/s {{.Compass}}", "10 m/s SW\n"},
+		{"Precipitation conversion", "{{.PrecipitationIn \"inch\"}}", "0.5\n"},
+		{"Derived fields", "{{.Header}} | {{.UVCategory}} | {{compass 90}} | {{upper .Weather.WeatherCodeDesc}}", "Tokyo, JP | high | E | CLEAR\n"},
+		{"Color disabled", "{{color \"red\" .Location.Name}}", "Tokyo\n"},
+		{"Trailing newline kept", "{{.Location.Name}}\n", "Tokyo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.text, Style{})
+			require.NoError(t, err)
+
+			output, err := tmpl.Execute(location, weather)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestTemplate_Color(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{color "red" "hot"}}`, Style{Mode: Color16})
+	require.NoError(t, err)
+
+	output, err := tmpl.Execute(&api.Location{}, &api.Weather{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "\033[31mhot\033[0m\n", output)
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	_, err := ParseTemplate("{{.Location.Name", Style{})
+	assert.ErrorContains(t, err, "invalid template")
+
+	for _, text := range []string{
+		"{{.Nope}}",
+		`{{color "chartreuse" "x"}}`,
+		`{{.TemperatureIn "rankine"}}`,
+	} {
+		tmpl, err := ParseTemplate(text, Style{})
+		require.NoError(t, err, text)
+
+		_, err = tmpl.Execute(&api.Location{}, &api.Weather{})
+		assert.ErrorContains(t, err, "failed to render template", text)
+	}
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FormatWatchStatus formats the status line of watch mode: the age of the
+// reading updated at updated, or that none arrived yet, and the interval
+// between updates
+func FormatWatchStatus(updated, now time.Time, interval time.Duration) string {
+	state := "Waiting for the first reading"
+	if !updated.IsZero() {
+		state = "Updated " + FormatAge(now.Sub(updated))
+	}
+	return fmt.Sprintf("%s · every %s · Ctrl+C to quit\n", state, FormatDuration(interval))
+}
+
+// FormatWatchBanner formats the failure of an update at failed, shown above
+// the last good reading until an update succeeds
+func FormatWatchBanner(err error, failed time.Time, retry time.Duration) string {
+	return fmt.Sprintf("⚠ Update failed at %s, retrying in %s: %v\n", failed.Format("15:04"), FormatDuration(retry), err)
+}
+
+// FormatDuration formats d without zero units, e.g. "10m" instead of "10m0s"
+func FormatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
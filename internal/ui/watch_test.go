@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWatchStatus(t *testing.T) {
+	now := time.Date(2025, 3, 1, 14, 5, 0, 0, time.UTC)
+
+	assert.Equal(t, "Updated 3 min ago · every 10m · Ctrl+C to quit\n",
+		FormatWatchStatus(now.Add(-3*time.Minute-20*time.Second), now, 10*time.Minute))
+	assert.Equal(t, "Waiting for the first reading · every 1h30m · Ctrl+C to quit\n",
+		FormatWatchStatus(time.Time{}, now, 90*time.Minute))
+}
+
+func TestFormatWatchBanner(t *testing.T) {
+	failed := time.Date(2025, 3, 1, 14, 5, 0, 0, time.UTC)
+
+	assert.Equal(t, "⚠ Update failed at 14:05, retrying in 1m: connection refused\n",
+		FormatWatchBanner(errors.New("connection refused"), failed, time.Minute))
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "10m", FormatDuration(10*time.Minute))
+	assert.Equal(t, "2h", FormatDuration(2*time.Hour))
+	assert.Equal(t, "1h30m", FormatDuration(90*time.Minute))
+	assert.Equal(t, "45s", FormatDuration(45*time.Second))
+	assert.Equal(t, "1m30s", FormatDuration(90*time.Second))
+}